@@ -0,0 +1,247 @@
+package lark_sdk
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultAlertCardId   = "AAq3zkrIEYCqR"
+	defaultAlertInterval = time.Minute
+)
+
+type alertMsg struct {
+	AppId   string `json:"app_id"`
+	AppName string `json:"app_name"`
+	Err     string `json:"err"`
+	ErrTime string `json:"err_time"`
+	Logid   string `json:"logid"`
+}
+
+// loggerSetter 由 NewClient 注入 WithLogger 设置的日志，用于记录告警本身发送失败
+type loggerSetter interface {
+	setLogger(logger larkcore.Logger)
+}
+
+func newAlertMsg(appId, appName string, err error) alertMsg {
+	if appName == "" {
+		appName = "未知"
+	}
+	return alertMsg{
+		AppId:   appId,
+		AppName: appName,
+		Err:     err.Error(),
+		ErrTime: time.Now().Format(time.DateTime),
+		Logid:   logIdOf(err),
+	}
+}
+
+type cardAlerter struct {
+	client  LarkClient
+	appId   string
+	appName string
+	userId  string
+	cardId  string
+	logger  larkcore.Logger
+}
+
+// NewCardAlerter 通过 client 向 userId 发送告警卡片，appId/appName 为产生错误的应用
+func NewCardAlerter(client LarkClient, appId, appName, userId string) Alerter {
+	return &cardAlerter{
+		client:  client,
+		appId:   appId,
+		appName: appName,
+		userId:  userId,
+		cardId:  defaultAlertCardId,
+		logger:  larkcore.NewDefaultLogger(larkcore.LogLevelError),
+	}
+}
+
+func (a *cardAlerter) setLogger(logger larkcore.Logger) {
+	a.logger = logger
+}
+
+func (a *cardAlerter) Alert(err error) {
+	msg := newAlertMsg(a.appId, a.appName, err)
+	ctx := context.Background()
+	if sendErr := a.client.SendCardMsg(ctx, UserId, a.userId, a.cardId, msg); sendErr != nil {
+		a.logger.Error(ctx, fmt.Sprintf("lark sdk: send alert card failed: %v, alert: %v", sendErr, err))
+	}
+}
+
+type webhookAlerter struct {
+	url        string
+	secret     string
+	appId      string
+	appName    string
+	httpClient *http.Client
+	logger     larkcore.Logger
+}
+
+// NewWebhookAlerter 通过群自定义机器人发送告警，secret 为空时不签名
+func NewWebhookAlerter(url, secret, appId, appName string) Alerter {
+	return &webhookAlerter{
+		url:        url,
+		secret:     secret,
+		appId:      appId,
+		appName:    appName,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		logger:     larkcore.NewDefaultLogger(larkcore.LogLevelError),
+	}
+}
+
+func (a *webhookAlerter) setLogger(logger larkcore.Logger) {
+	a.logger = logger
+}
+
+func (a *webhookAlerter) Alert(err error) {
+	if sendErr := a.send(newAlertMsg(a.appId, a.appName, err)); sendErr != nil {
+		a.logger.Error(context.Background(), fmt.Sprintf("lark sdk: send webhook alert failed: %v, alert: %v", sendErr, err))
+	}
+}
+
+func (a *webhookAlerter) send(msg alertMsg) error {
+	body := map[string]interface{}{
+		"msg_type": "text",
+		"content": map[string]string{
+			"text": fmt.Sprintf("[%s] %s(%s)\n%s\nlogid: %s", msg.ErrTime, msg.AppName, msg.AppId, msg.Err, msg.Logid),
+		},
+	}
+	if a.secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		body["timestamp"] = timestamp
+		body["sign"] = webhookSign(timestamp, a.secret)
+	}
+	bs, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	resp, err := a.httpClient.Post(a.url, "application/json", bytes.NewReader(bs))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	var result struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return err
+	}
+	if result.Code != 0 {
+		return errors.Errorf("webhook alert failed, code: %d, msg: %s", result.Code, result.Msg)
+	}
+	return nil
+}
+
+func webhookSign(timestamp, secret string) string {
+	h := hmac.New(sha256.New, []byte(timestamp+"\n"+secret))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+type logAlerter struct {
+	logger  *slog.Logger
+	appId   string
+	appName string
+}
+
+// NewLogAlerter 把告警写入结构化日志，logger 为 nil 时使用 slog.Default()
+func NewLogAlerter(logger *slog.Logger, appId, appName string) Alerter {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &logAlerter{logger: logger, appId: appId, appName: appName}
+}
+
+func (a *logAlerter) Alert(err error) {
+	a.logger.Error("lark sdk error",
+		slog.String("app_id", a.appId),
+		slog.String("app_name", a.appName),
+		slog.String("err", err.Error()),
+		slog.String("logid", logIdOf(err)))
+}
+
+type nopAlerter struct{}
+
+// NewNopAlerter 丢弃所有告警
+func NewNopAlerter() Alerter {
+	return nopAlerter{}
+}
+
+func (nopAlerter) Alert(error) {}
+
+type throttleAlerter struct {
+	next     Alerter
+	interval time.Duration
+	mu       sync.Mutex
+	last     map[string]time.Time
+	dropped  map[string]int
+}
+
+// NewThrottleAlerter 对相同的告警去重，interval 内同一错误只转发一次，*LarkError 按接口和错误码判断是否相同，
+// 被丢弃的次数会附在下一次转发的告警里
+func NewThrottleAlerter(next Alerter, interval time.Duration) Alerter {
+	return &throttleAlerter{
+		next:     next,
+		interval: interval,
+		last:     make(map[string]time.Time),
+		dropped:  make(map[string]int),
+	}
+}
+
+func (a *throttleAlerter) setLogger(logger larkcore.Logger) {
+	if s, ok := a.next.(loggerSetter); ok {
+		s.setLogger(logger)
+	}
+}
+
+func (a *throttleAlerter) Alert(err error) {
+	if err == nil {
+		return
+	}
+	key := throttleKey(err)
+	now := time.Now()
+	a.mu.Lock()
+	if last, ok := a.last[key]; ok && now.Sub(last) < a.interval {
+		a.dropped[key]++
+		a.mu.Unlock()
+		return
+	}
+	dropped := a.dropped[key]
+	a.last[key] = now
+	delete(a.dropped, key)
+	// 过期的 key 直接清理，被丢弃的次数不再补发，避免错误内容很分散时 map 无限增长
+	for k, t := range a.last {
+		if now.Sub(t) >= a.interval {
+			delete(a.last, k)
+			delete(a.dropped, k)
+		}
+	}
+	a.mu.Unlock()
+	if dropped > 0 {
+		err = errors.Wrapf(err, "suppressed %d times in last %s", dropped, a.interval)
+	}
+	a.next.Alert(err)
+}
+
+// throttleKey 去重用的 key，LarkError 的 log_id 每次请求都不同，按接口和错误码去重
+func throttleKey(err error) string {
+	var larkErr *LarkError
+	if errors.As(err, &larkErr) {
+		return fmt.Sprintf("%s:%d", larkErr.Method, larkErr.Code)
+	}
+	return err.Error()
+}
@@ -0,0 +1,137 @@
+package lark_sdk
+
+import (
+	"context"
+	"encoding/json"
+
+	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
+	larkapproval "github.com/larksuite/oapi-sdk-go/v3/service/approval/v4"
+)
+
+const instTitleKey = "@i18n@title"
+
+// CreateInstOption 创建审批实例的可选参数，传给 CreateApprovalInst
+type CreateInstOption func(*larkapproval.InstanceCreateBuilder)
+
+// WithInstUuid 幂等 key，同一租户下同一个 uuid 只能创建一个实例，重复时返回的错误满足 errors.Is(err, ErrDuplicateUuid)
+func WithInstUuid(uuid string) CreateInstOption {
+	return func(b *larkapproval.InstanceCreateBuilder) {
+		b.Uuid(uuid)
+	}
+}
+
+// WithInstOpenId 以 open_id 指定发起人，此时 CreateApprovalInst 的 userId 传空串
+func WithInstOpenId(openId string) CreateInstOption {
+	return func(b *larkapproval.InstanceCreateBuilder) {
+		b.OpenId(openId)
+	}
+}
+
+// WithInstDept 发起人属于多个部门时指定发起部门的 department_id，默认为第一个部门
+func WithInstDept(departmentId string) CreateInstOption {
+	return func(b *larkapproval.InstanceCreateBuilder) {
+		b.DepartmentId(departmentId)
+	}
+}
+
+// WithInstTitle 自定义实例标题，替换审批名称显示
+func WithInstTitle(title string) CreateInstOption {
+	return func(b *larkapproval.InstanceCreateBuilder) {
+		b.Title(instTitleKey).
+			TitleDisplayMethod(0).
+			I18nResources([]*larkapproval.I18nResource{
+				larkapproval.NewI18nResourceBuilder().
+					Locale("zh-CN").
+					IsDefault(true).
+					Texts([]*larkapproval.I18nResourceText{
+						larkapproval.NewI18nResourceTextBuilder().
+							Key(instTitleKey).
+							Value(title).
+							Build(),
+					}).
+					Build(),
+			})
+	}
+}
+
+// WithInstApproverOpenId 发起人自选节点的审批人 open_id，与 nodeApprover 取并集
+func WithInstApproverOpenId(nodeApprover []*larkapproval.NodeApprover) CreateInstOption {
+	return func(b *larkapproval.InstanceCreateBuilder) {
+		b.NodeApproverOpenIdList(nodeApprover)
+	}
+}
+
+// WithInstCc 发起人自选节点的抄送人 user_id，单个节点最多 20 人
+func WithInstCc(nodeCc []*larkapproval.NodeCc) CreateInstOption {
+	return func(b *larkapproval.InstanceCreateBuilder) {
+		b.NodeCcUserIdList(nodeCc)
+	}
+}
+
+// WithInstCcOpenId 发起人自选节点的抄送人 open_id，单个节点最多 20 人
+func WithInstCcOpenId(nodeCc []*larkapproval.NodeCc) CreateInstOption {
+	return func(b *larkapproval.InstanceCreateBuilder) {
+		b.NodeCcOpenIdList(nodeCc)
+	}
+}
+
+// WithInstAllowResubmit 被拒绝后允许再次提交
+func WithInstAllowResubmit() CreateInstOption {
+	return func(b *larkapproval.InstanceCreateBuilder) {
+		b.AllowResubmit(true)
+	}
+}
+
+// WithInstAllowSubmitAgain 允许重新提交（复制后提交）
+func WithInstAllowSubmitAgain() CreateInstOption {
+	return func(b *larkapproval.InstanceCreateBuilder) {
+		b.AllowSubmitAgain(true)
+	}
+}
+
+// CreateApprovalInst 创建审批实例，返回实例 code。
+// form 可以是 *FormBuilder，或者会被序列化为控件值数组的任意值；nodeApprover 为发起人自选节点的审批人 user_id
+func (c *larkClient) CreateApprovalInst(ctx context.Context, approvalCode, userId string, form interface{}, nodeApprover []*larkapproval.NodeApprover, opts ...CreateInstOption) (string, error) {
+	if builder, ok := form.(*FormBuilder); ok {
+		built, err := builder.Build()
+		if err != nil {
+			return "", err
+		}
+		form = built
+	}
+	bytes, err := json.Marshal(form)
+	if err != nil {
+		c.Alert(err)
+		return "", err
+	}
+	instBuilder := larkapproval.NewInstanceCreateBuilder().
+		ApprovalCode(approvalCode).
+		Form(string(bytes)).
+		NodeApproverUserIdList(nodeApprover)
+	if userId != "" {
+		instBuilder.UserId(userId)
+	}
+	for _, opt := range opts {
+		opt(instBuilder)
+	}
+	inst := instBuilder.Build()
+	req := larkapproval.NewCreateInstanceReqBuilder().
+		InstanceCreate(inst).
+		Build()
+	resp, err := c.client.Approval.Instance.Create(ctx, req)
+	if err != nil {
+		c.Alert(err)
+		return "", err
+	}
+	if !resp.Success() {
+		err = newLarkError("CreateApprovalInst", resp.ApiResp, resp.CodeError, "approval_code", approvalCode, "user_id", userId, "uuid", larkcore.StringValue(inst.Uuid))
+		c.Alert(err)
+		return "", err
+	}
+	if resp.Data == nil {
+		err = newEmptyDataError("CreateApprovalInst", resp.ApiResp, "approval_code", approvalCode, "user_id", userId)
+		c.Alert(err)
+		return "", err
+	}
+	return larkcore.StringValue(resp.Data.InstanceCode), nil
+}
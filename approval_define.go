@@ -0,0 +1,322 @@
+package lark_sdk
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
+	larkapproval "github.com/larksuite/oapi-sdk-go/v3/service/approval/v4"
+)
+
+// 审批节点的审批方式
+const (
+	NodeAnd        = "AND"        // 会签
+	NodeOr         = "OR"         // 或签
+	NodeSequential = "SEQUENTIAL" // 依次审批
+	NodeCcSend     = "CC_SEND"    // 抄送
+)
+
+// 发起节点、结束节点的 node_id，所有审批定义相同
+const (
+	StartNodeId = "b078ffd28db767c502ac367053f6e0ac"
+	EndNodeId   = "b1a326c06d88bf042f73d70f50197905"
+)
+
+// 发起人自选审批人的范围
+const (
+	ApproverRangeAll  = 0
+	ApproverRangeRole = 1
+	ApproverRangeUser = 2
+)
+
+// ApprovalDefinition 解析后的审批定义
+type ApprovalDefinition struct {
+	Code     string
+	Name     string
+	Status   string
+	Widgets  []WidgetDef
+	Nodes    []ApprovalNode
+	Viewers  []ApprovalViewer
+	AdminIds []string
+	Raw      *larkapproval.GetApprovalRespData
+}
+
+// ApprovalNode 审批节点，NeedApprover 为 true 时是发起人自选节点，ApproverRanges 为可选审批人的范围
+type ApprovalNode struct {
+	Id               string
+	CustomId         string
+	Name             string
+	NodeType         string
+	NeedApprover     bool
+	ApproverMulti    bool
+	ApproverRanges   []ApproverRange
+	RequireSignature bool
+}
+
+// ApproverRange 自选审批人的范围，Type 为 ApproverRangeAll、ApproverRangeRole 或 ApproverRangeUser，
+// Ids 对应为空、角色 ID 或 user_id
+type ApproverRange struct {
+	Type int
+	Ids  []string
+}
+
+// ApprovalViewer 审批定义的可见人，Type 为 TENANT、DEPARTMENT、USER、ROLE、USER_GROUP 或 NONE
+type ApprovalViewer struct {
+	Type   string
+	Id     string
+	UserId string
+}
+
+// Key 节点的自定义 ID，没有时为 node_id，可用作 RollbackApprovalTask 的 defKeys
+func (n ApprovalNode) Key() string {
+	if n.CustomId != "" {
+		return n.CustomId
+	}
+	return n.Id
+}
+
+// GetApprovalDefinition 获取并解析审批定义
+func (c *larkClient) GetApprovalDefinition(ctx context.Context, code string) (*ApprovalDefinition, error) {
+	define, err := c.GetApprovalDefineByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	return NewApprovalDefinition(code, define)
+}
+
+// NewApprovalDefinition 解析 GetApprovalDefineByCode 的返回值
+func NewApprovalDefinition(code string, data *larkapproval.GetApprovalRespData) (*ApprovalDefinition, error) {
+	res := &ApprovalDefinition{
+		Code:     code,
+		Name:     larkcore.StringValue(data.ApprovalName),
+		Status:   larkcore.StringValue(data.Status),
+		Nodes:    make([]ApprovalNode, 0, len(data.NodeList)),
+		Viewers:  make([]ApprovalViewer, 0, len(data.Viewers)),
+		AdminIds: data.ApprovalAdminIds,
+		Raw:      data,
+	}
+	if form := larkcore.StringValue(data.Form); form != "" {
+		widgets, err := ParseWidgetDefs(form)
+		if err != nil {
+			return nil, err
+		}
+		res.Widgets = widgets
+	}
+	for _, node := range data.NodeList {
+		n := ApprovalNode{
+			Id:               larkcore.StringValue(node.NodeId),
+			CustomId:         larkcore.StringValue(node.CustomNodeId),
+			Name:             larkcore.StringValue(node.Name),
+			NodeType:         larkcore.StringValue(node.NodeType),
+			NeedApprover:     larkcore.BoolValue(node.NeedApprover),
+			ApproverMulti:    larkcore.BoolValue(node.ApproverChosenMulti),
+			RequireSignature: larkcore.BoolValue(node.RequireSignature),
+		}
+		for _, r := range node.ApproverChosenRange {
+			n.ApproverRanges = append(n.ApproverRanges, ApproverRange{
+				Type: larkcore.IntValue(r.ApproverRangeType),
+				Ids:  r.ApproverRangeIds,
+			})
+		}
+		res.Nodes = append(res.Nodes, n)
+	}
+	for _, viewer := range data.Viewers {
+		res.Viewers = append(res.Viewers, ApprovalViewer{
+			Type:   larkcore.StringValue(viewer.Type),
+			Id:     larkcore.StringValue(viewer.Id),
+			UserId: larkcore.StringValue(viewer.UserId),
+		})
+	}
+	return res, nil
+}
+
+// Widget 按名称查找控件，明细中的控件使用 "明细名称.控件名称"
+func (d *ApprovalDefinition) Widget(name string) *WidgetDef {
+	defs := d.Widgets
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		def := findWidgetDef(defs, part)
+		if def == nil || i == len(parts)-1 {
+			return def
+		}
+		defs = def.Children
+	}
+	return nil
+}
+
+// Node 按名称查找节点
+func (d *ApprovalDefinition) Node(name string) *ApprovalNode {
+	for i := range d.Nodes {
+		if d.Nodes[i].Name == name {
+			return &d.Nodes[i]
+		}
+	}
+	return nil
+}
+
+// NodeKeys 按名称查找节点的 Key，用作 RollbackApprovalTask 的 defKeys，找不到的节点忽略
+func (d *ApprovalDefinition) NodeKeys(names ...string) []string {
+	res := make([]string, 0, len(names))
+	for _, name := range names {
+		if node := d.Node(name); node != nil {
+			res = append(res, node.Key())
+		}
+	}
+	return res
+}
+
+// 定义变更的类型
+const (
+	ChangeAdded    = "added"
+	ChangeRemoved  = "removed"
+	ChangeModified = "modified"
+)
+
+// DefinitionChange 两个版本的审批定义之间的一处差异。
+// Target 为 widget 或 node；Path 为控件或节点名称，明细中的控件为 "明细名称.控件名称"；
+// Field 为变化的属性，Added、Removed 时为空。Breaking 表示已有的集成可能因此失败
+type DefinitionChange struct {
+	Kind     string
+	Target   string
+	Id       string
+	Path     string
+	Field    string
+	Old      string
+	New      string
+	Breaking bool
+}
+
+func (c DefinitionChange) String() string {
+	s := fmt.Sprintf("%s %s %s(%s)", c.Target, c.Kind, c.Path, c.Id)
+	if c.Field != "" {
+		s += fmt.Sprintf(" %s: %q -> %q", c.Field, c.Old, c.New)
+	}
+	if c.Breaking {
+		s += " [breaking]"
+	}
+	return s
+}
+
+// Diff 对比 d 与新版本 other，控件和节点按 ID 匹配
+func (d *ApprovalDefinition) Diff(other *ApprovalDefinition) []DefinitionChange {
+	res := make([]DefinitionChange, 0)
+	res = diffWidgets(res, "", d.Widgets, other.Widgets)
+	return diffNodes(res, d.Nodes, other.Nodes)
+}
+
+// HasBreakingChange 对比结果中是否有可能导致集成失败的变化
+func HasBreakingChange(changes []DefinitionChange) bool {
+	for _, c := range changes {
+		if c.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+func diffWidgets(res []DefinitionChange, prefix string, olds, news []WidgetDef) []DefinitionChange {
+	newById := make(map[string]*WidgetDef, len(news))
+	for i := range news {
+		newById[news[i].ID] = &news[i]
+	}
+	oldIds := make(map[string]bool, len(olds))
+	for _, o := range olds {
+		oldIds[o.ID] = true
+		n, ok := newById[o.ID]
+		if !ok {
+			res = append(res, DefinitionChange{Kind: ChangeRemoved, Target: "widget", Id: o.ID, Path: prefix + o.Name, Breaking: true})
+			continue
+		}
+		path := prefix + n.Name
+		modified := func(field, from, to string, breaking bool) {
+			res = append(res, DefinitionChange{Kind: ChangeModified, Target: "widget", Id: o.ID, Path: path, Field: field, Old: from, New: to, Breaking: breaking})
+		}
+		if o.Name != n.Name {
+			// 按名称赋值、解析表单的代码会失效
+			modified("name", o.Name, n.Name, true)
+		}
+		if o.Type != n.Type {
+			modified("type", o.Type, n.Type, true)
+		}
+		if o.Required != n.Required {
+			modified("required", fmt.Sprint(o.Required), fmt.Sprint(n.Required), n.Required)
+		}
+		if removed, added := diffOptions(o.Options, n.Options); removed != "" || added != "" {
+			modified("options", removed, added, removed != "")
+		}
+		res = diffWidgets(res, path+".", o.Children, n.Children)
+	}
+	for _, n := range news {
+		if !oldIds[n.ID] {
+			res = append(res, DefinitionChange{Kind: ChangeAdded, Target: "widget", Id: n.ID, Path: prefix + n.Name, Breaking: n.Required})
+		}
+	}
+	return res
+}
+
+// diffOptions 返回删除和新增的选项，选项按 value 匹配，value 相同但 text 变化视为删除后新增
+func diffOptions(olds, news []WidgetOption) (string, string) {
+	oldSet := make(map[WidgetOption]bool, len(olds))
+	for _, o := range olds {
+		oldSet[o] = true
+	}
+	newSet := make(map[WidgetOption]bool, len(news))
+	for _, n := range news {
+		newSet[n] = true
+	}
+	removed, added := make([]string, 0), make([]string, 0)
+	for _, o := range olds {
+		if !newSet[o] {
+			removed = append(removed, o.Text)
+		}
+	}
+	for _, n := range news {
+		if !oldSet[n] {
+			added = append(added, n.Text)
+		}
+	}
+	sort.Strings(removed)
+	sort.Strings(added)
+	return strings.Join(removed, ","), strings.Join(added, ",")
+}
+
+func diffNodes(res []DefinitionChange, olds, news []ApprovalNode) []DefinitionChange {
+	newById := make(map[string]*ApprovalNode, len(news))
+	for i := range news {
+		newById[news[i].Id] = &news[i]
+	}
+	oldIds := make(map[string]bool, len(olds))
+	for _, o := range olds {
+		oldIds[o.Id] = true
+		n, ok := newById[o.Id]
+		if !ok {
+			res = append(res, DefinitionChange{Kind: ChangeRemoved, Target: "node", Id: o.Id, Path: o.Name, Breaking: true})
+			continue
+		}
+		modified := func(field, from, to string, breaking bool) {
+			res = append(res, DefinitionChange{Kind: ChangeModified, Target: "node", Id: o.Id, Path: n.Name, Field: field, Old: from, New: to, Breaking: breaking})
+		}
+		if o.Name != n.Name {
+			modified("name", o.Name, n.Name, true)
+		}
+		if o.CustomId != n.CustomId {
+			// 回退使用的 defKeys 会失效
+			modified("custom_id", o.CustomId, n.CustomId, true)
+		}
+		if o.NodeType != n.NodeType {
+			modified("node_type", o.NodeType, n.NodeType, false)
+		}
+		if o.NeedApprover != n.NeedApprover {
+			// 变为自选节点时创建实例需要传入审批人
+			modified("need_approver", fmt.Sprint(o.NeedApprover), fmt.Sprint(n.NeedApprover), n.NeedApprover)
+		}
+	}
+	for _, n := range news {
+		if !oldIds[n.Id] {
+			res = append(res, DefinitionChange{Kind: ChangeAdded, Target: "node", Id: n.Id, Path: n.Name, Breaking: n.NeedApprover})
+		}
+	}
+	return res
+}
@@ -0,0 +1,211 @@
+package lark_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/larksuite/oapi-sdk-go/v3/core/httpserverext"
+	larkevent "github.com/larksuite/oapi-sdk-go/v3/event"
+	"github.com/larksuite/oapi-sdk-go/v3/event/dispatcher"
+	larkws "github.com/larksuite/oapi-sdk-go/v3/ws"
+	"github.com/pkg/errors"
+)
+
+// 审批事件类型，需要先用 SubscribeApproval 订阅审批定义
+const (
+	EventApprovalInstance = "approval_instance"
+	EventApprovalTask     = "approval_task"
+	EventApprovalCc       = "approval_cc"
+)
+
+// InstanceStatusEvent 审批实例状态变更，Status 还可能为 REVERTED（已撤销）
+type InstanceStatusEvent struct {
+	Uuid         string // 事件 id，重试时不变，可用于去重
+	AppId        string
+	TenantKey    string
+	ApprovalCode string
+	InstanceCode string
+	Status       InstanceStatus
+	OperateTime  time.Time
+}
+
+// TaskStatusEvent 审批任务状态变更，Status 还可能为 ROLLBACK、OVERTIME_CLOSE 等；
+// DefKey、CustomKey 为任务所在节点的 node_id 和自定义 ID
+type TaskStatusEvent struct {
+	Uuid         string
+	AppId        string
+	TenantKey    string
+	ApprovalCode string
+	InstanceCode string
+	TaskId       string
+	UserId       string
+	OpenId       string
+	Status       TaskStatus
+	DefKey       string
+	CustomKey    string
+	OperateTime  time.Time
+}
+
+// CcEvent 审批抄送，UserId 为被抄送人，FromUserId 为抄送人
+type CcEvent struct {
+	Uuid         string
+	AppId        string
+	TenantKey    string
+	ApprovalCode string
+	InstanceCode string
+	Id           string
+	UserId       string
+	FromUserId   string
+	CreateTime   time.Time
+}
+
+// approvalEventMsg 审批事件的消息体，审批事件为 1.0 版本的格式
+type approvalEventMsg struct {
+	Uuid  string `json:"uuid"`
+	Token string `json:"token"`
+	Event struct {
+		AppId        string      `json:"app_id"`
+		TenantKey    string      `json:"tenant_key"`
+		Type         string      `json:"type"`
+		ApprovalCode string      `json:"approval_code"`
+		InstanceCode string      `json:"instance_code"`
+		Status       string      `json:"status"`
+		OperateTime  json.Number `json:"operate_time"`
+		TaskId       string      `json:"task_id"`
+		UserId       string      `json:"user_id"`
+		OpenId       string      `json:"open_id"`
+		DefKey       string      `json:"def_key"`
+		CustomKey    string      `json:"custom_key"`
+		Id           string      `json:"id"`
+		From         string      `json:"from"`
+		CreateTime   json.Number `json:"create_time"`
+	} `json:"event"`
+}
+
+// ApprovalEventHandler 接收审批事件并分发到各回调，基于开放平台 SDK 的 EventDispatcher，
+// 负责校验签名、解密和 URL 验证。可以作为 http.Handler 使用，也可以通过长连接接收事件
+type ApprovalEventHandler struct {
+	dispatcher        *dispatcher.EventDispatcher
+	verificationToken string
+}
+
+// NewApprovalEventHandler verificationToken、encryptKey 为开发者后台「事件与回调」中的配置，未开启加密时 encryptKey 传空串
+func NewApprovalEventHandler(verificationToken, encryptKey string) *ApprovalEventHandler {
+	return &ApprovalEventHandler{
+		dispatcher:        dispatcher.NewEventDispatcher(verificationToken, encryptKey),
+		verificationToken: verificationToken,
+	}
+}
+
+// Dispatcher 底层的 EventDispatcher，可以注册其他事件
+func (h *ApprovalEventHandler) Dispatcher() *dispatcher.EventDispatcher {
+	return h.dispatcher
+}
+
+// OnInstanceStatusChanged 审批实例状态变更，每种回调只能注册一次。回调返回错误时开放平台会重试推送
+func (h *ApprovalEventHandler) OnInstanceStatusChanged(fn func(ctx context.Context, event *InstanceStatusEvent) error) *ApprovalEventHandler {
+	h.on(EventApprovalInstance, func(ctx context.Context, msg *approvalEventMsg) error {
+		e := msg.Event
+		return fn(ctx, &InstanceStatusEvent{
+			Uuid:         msg.Uuid,
+			AppId:        e.AppId,
+			TenantKey:    e.TenantKey,
+			ApprovalCode: e.ApprovalCode,
+			InstanceCode: e.InstanceCode,
+			Status:       InstanceStatus(e.Status),
+			OperateTime:  eventTime(e.OperateTime),
+		})
+	})
+	return h
+}
+
+// OnTaskStatusChanged 审批任务状态变更
+func (h *ApprovalEventHandler) OnTaskStatusChanged(fn func(ctx context.Context, event *TaskStatusEvent) error) *ApprovalEventHandler {
+	h.on(EventApprovalTask, func(ctx context.Context, msg *approvalEventMsg) error {
+		e := msg.Event
+		return fn(ctx, &TaskStatusEvent{
+			Uuid:         msg.Uuid,
+			AppId:        e.AppId,
+			TenantKey:    e.TenantKey,
+			ApprovalCode: e.ApprovalCode,
+			InstanceCode: e.InstanceCode,
+			TaskId:       e.TaskId,
+			UserId:       e.UserId,
+			OpenId:       e.OpenId,
+			Status:       TaskStatus(e.Status),
+			DefKey:       e.DefKey,
+			CustomKey:    e.CustomKey,
+			OperateTime:  eventTime(e.OperateTime),
+		})
+	})
+	return h
+}
+
+// OnCc 审批抄送
+func (h *ApprovalEventHandler) OnCc(fn func(ctx context.Context, event *CcEvent) error) *ApprovalEventHandler {
+	h.on(EventApprovalCc, func(ctx context.Context, msg *approvalEventMsg) error {
+		e := msg.Event
+		return fn(ctx, &CcEvent{
+			Uuid:         msg.Uuid,
+			AppId:        e.AppId,
+			TenantKey:    e.TenantKey,
+			ApprovalCode: e.ApprovalCode,
+			InstanceCode: e.InstanceCode,
+			Id:           e.Id,
+			UserId:       e.UserId,
+			FromUserId:   e.From,
+			CreateTime:   eventTime(e.CreateTime),
+		})
+	})
+	return h
+}
+
+// on 注册 1.0 事件。自定义事件的回调拿到的是原始请求，SDK 不会解密，也只在 URL 验证时校验 token，
+// 所以这里自行解密并校验 token，否则未开启加密时任何人都可以伪造事件
+func (h *ApprovalEventHandler) on(eventType string, fn func(ctx context.Context, msg *approvalEventMsg) error) {
+	h.dispatcher.OnCustomizedEvent(eventType, func(ctx context.Context, req *larkevent.EventReq) error {
+		body := req.Body
+		var encrypted larkevent.EventEncryptMsg
+		if json.Unmarshal(body, &encrypted) == nil && encrypted.Encrypt != "" {
+			plain, err := h.dispatcher.DecryptEvent(ctx, encrypted.Encrypt)
+			if err != nil {
+				return err
+			}
+			body = []byte(plain)
+		}
+		msg := &approvalEventMsg{}
+		if err := json.Unmarshal(body, msg); err != nil {
+			return err
+		}
+		// 长连接收到的事件没有请求头，连接本身已鉴权
+		if req.Header != nil && h.verificationToken != "" && msg.Token != h.verificationToken {
+			return errors.Errorf("approval event %s: invalid verification token", msg.Uuid)
+		}
+		return fn(ctx, msg)
+	})
+}
+
+// ServeHTTP 处理开放平台推送的事件请求
+func (h *ApprovalEventHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	httpserverext.NewEventHandlerFunc(h.dispatcher)(w, r)
+}
+
+// StartApprovalEventClient 通过长连接接收审批事件，无需公网地址。连接成功后一直阻塞，只在连接失败时返回。
+// 需要在开发者后台把订阅方式设置为「使用长连接接收事件」
+func (c *larkClient) StartApprovalEventClient(ctx context.Context, h *ApprovalEventHandler) error {
+	return larkws.NewClient(c.appId, c.appSecret, larkws.WithEventHandler(h.dispatcher)).Start(ctx)
+}
+
+// eventTime 事件中的时间戳有毫秒和秒两种，可能是字符串
+func eventTime(n json.Number) time.Time {
+	ts, err := n.Int64()
+	if err != nil || ts == 0 {
+		return time.Time{}
+	}
+	if ts < 1e12 {
+		return time.Unix(ts, 0)
+	}
+	return time.UnixMilli(ts)
+}
@@ -0,0 +1,234 @@
+package lark_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
+	"github.com/pkg/errors"
+)
+
+// 审批文件上传的类型
+const (
+	ApprovalFileAttachment = "attachment"
+	ApprovalFileImage      = "image"
+)
+
+const approvalFileUploadPath = "/approval/openapi/v2/file/upload"
+
+// approvalFileUploadUrl 审批文件上传不在开放平台域名下，open.feishu.cn 对应 www.feishu.cn，
+// open.larksuite.com 对应 www.larksuite.com，其他域名（如私有化部署）直接使用
+func approvalFileUploadUrl(baseUrl string) string {
+	u, err := url.Parse(baseUrl)
+	if err != nil || u.Host == "" {
+		return baseUrl + approvalFileUploadPath
+	}
+	if strings.HasPrefix(u.Host, "open.") {
+		u.Host = "www." + strings.TrimPrefix(u.Host, "open.")
+	}
+	u.Path = approvalFileUploadPath
+	return u.String()
+}
+
+// FormFile 审批表单中附件、图片控件里的一个文件，URL 为临时下载链接
+type FormFile struct {
+	WidgetId   string
+	WidgetName string
+	WidgetType string
+	Index      int // 在控件中的序号
+	Name       string
+	URL        string
+}
+
+// FileSink 接收下载的文件，r 只在回调期间有效
+type FileSink func(ctx context.Context, file FormFile, r io.Reader) error
+
+// DirSink 把文件保存到 dir 目录，同名文件自动加序号
+func DirSink(dir string) FileSink {
+	return func(ctx context.Context, file FormFile, r io.Reader) error {
+		_, err := saveFile(dir, file.Name, r)
+		return err
+	}
+}
+
+func isFileWidget(widgetType string) bool {
+	switch widgetType {
+	case WidgetAttachment, WidgetAttachmentV1, WidgetImage, WidgetImageV2:
+		return true
+	}
+	return false
+}
+
+// FormFiles 列出表单中附件、图片控件里的文件，包括明细中的控件。附件控件的 ext 为逗号分隔的文件名，
+// 与 value 中的链接一一对应；图片控件没有文件名，使用链接中的文件名
+func FormFiles(widgets []FormWidget) []FormFile {
+	res := make([]FormFile, 0)
+	for _, widget := range widgets {
+		if widget.Type == WidgetFieldList {
+			rows, _ := widget.AsFieldList()
+			for _, row := range rows {
+				res = append(res, FormFiles(row)...)
+			}
+			continue
+		}
+		if !isFileWidget(widget.Type) {
+			continue
+		}
+		urls := toStrings(widget.Value)
+		var names []string
+		if ext, ok := widget.Ext.(string); ok && ext != "" {
+			names = strings.Split(ext, ",")
+		}
+		for i, u := range urls {
+			name := ""
+			if i < len(names) && len(names) == len(urls) {
+				name = names[i]
+			}
+			if name == "" {
+				name = fileNameOfUrl(u)
+			}
+			res = append(res, FormFile{
+				WidgetId:   widget.ID,
+				WidgetName: widget.Name,
+				WidgetType: widget.Type,
+				Index:      i,
+				Name:       sanitizeFileName(name, fmt.Sprintf("%s_%d", widget.Name, i+1)),
+				URL:        u,
+			})
+		}
+	}
+	return res
+}
+
+// toStrings 控件的 value 可能是字符串数组或逗号分隔的字符串
+func toStrings(v interface{}) []string {
+	res := make([]string, 0)
+	switch value := v.(type) {
+	case []interface{}:
+		for _, item := range value {
+			if s, ok := item.(string); ok && s != "" {
+				res = append(res, s)
+			}
+		}
+	case []string:
+		for _, s := range value {
+			if s != "" {
+				res = append(res, s)
+			}
+		}
+	case string:
+		for _, s := range strings.Split(value, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				res = append(res, s)
+			}
+		}
+	}
+	return res
+}
+
+func fileNameOfUrl(rawUrl string) string {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return ""
+	}
+	return path.Base(u.Path)
+}
+
+// DownloadFormFiles 逐个下载表单中的附件和图片并交给 sink，sink 返回错误时停止。
+// 单个文件超过 WithMaxDownloadSize 限制时返回 ErrFileTooLarge。未配置 WithHTTPClient 时单个文件的超时时间为 10 分钟
+func (c *larkClient) DownloadFormFiles(ctx context.Context, widgets []FormWidget, sink FileSink) error {
+	for _, file := range FormFiles(widgets) {
+		if err := c.downloadFormFile(ctx, file, sink); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *larkClient) downloadFormFile(ctx context.Context, file FormFile, sink FileSink) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, file.URL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.downloadHttpClient().Do(req)
+	if err != nil {
+		c.Alert(err)
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		err = errors.Errorf("download %s of %s failed, status: %d", file.Name, file.WidgetName, resp.StatusCode)
+		c.Alert(err)
+		return err
+	}
+	if resp.ContentLength > c.maxDownloadSize {
+		return errors.Wrapf(ErrFileTooLarge, "download %s of %s, limit: %d", file.Name, file.WidgetName, c.maxDownloadSize)
+	}
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil && params["filename"] != "" && (file.WidgetType == WidgetImage || file.WidgetType == WidgetImageV2) {
+		file.Name = sanitizeFileName(params["filename"], file.Name)
+	}
+	return sink(ctx, file, &limitedReader{r: resp.Body, n: c.maxDownloadSize})
+}
+
+// limitedReader 读取超过 n 字节时返回 ErrFileTooLarge，而不是像 io.LimitReader 一样静默截断
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n < 0 {
+		return 0, ErrFileTooLarge
+	}
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	if l.n < 0 {
+		return n, ErrFileTooLarge
+	}
+	return n, err
+}
+
+// UploadApprovalFile 上传审批附件或图片，fileType 为 ApprovalFileAttachment 或 ApprovalFileImage，
+// 返回的 code 用作创建审批实例时附件、图片控件的值
+func (c *larkClient) UploadApprovalFile(ctx context.Context, name, fileType string, r io.Reader) (string, error) {
+	resp, err := c.client.Do(ctx, &larkcore.ApiReq{
+		HttpMethod: http.MethodPost,
+		ApiPath:    approvalFileUploadUrl(c.baseUrl),
+		Body: larkcore.NewFormdata().
+			AddField("name", name).
+			AddField("type", fileType).
+			AddFile("content", r),
+		SupportedAccessTokenTypes: []larkcore.AccessTokenType{larkcore.AccessTokenTypeTenant},
+	})
+	if err != nil {
+		c.Alert(err)
+		return "", err
+	}
+	result := struct {
+		larkcore.CodeError
+		Data struct {
+			Code string `json:"code"`
+			Url  string `json:"url"`
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(resp.RawBody, &result); err != nil {
+		c.Alert(err)
+		return "", err
+	}
+	if result.Code != 0 {
+		err = newLarkError("UploadApprovalFile", resp, result.CodeError, "name", name, "type", fileType)
+		c.Alert(err)
+		return "", err
+	}
+	return result.Data.Code, nil
+}
@@ -0,0 +1,348 @@
+package lark_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"time"
+
+	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
+	larkapproval "github.com/larksuite/oapi-sdk-go/v3/service/approval/v4"
+)
+
+// InstanceStatus 审批实例状态
+type InstanceStatus string
+
+const (
+	InstPending  InstanceStatus = "PENDING"  // 审批中
+	InstApproved InstanceStatus = "APPROVED" // 通过
+	InstRejected InstanceStatus = "REJECTED" // 拒绝
+	InstCanceled InstanceStatus = "CANCELED" // 撤回
+	InstDeleted  InstanceStatus = "DELETED"  // 删除
+)
+
+// TaskStatus 审批任务状态
+type TaskStatus string
+
+const (
+	TaskPending     TaskStatus = "PENDING"     // 审批中
+	TaskApproved    TaskStatus = "APPROVED"    // 通过
+	TaskRejected    TaskStatus = "REJECTED"    // 拒绝
+	TaskTransferred TaskStatus = "TRANSFERRED" // 已转交
+	TaskDone        TaskStatus = "DONE"        // 完成，如或签节点中其他人已处理
+)
+
+// TimelineType 审批动态类型
+type TimelineType string
+
+const (
+	TimelineStart            TimelineType = "START"               // 发起
+	TimelinePass             TimelineType = "PASS"                // 通过
+	TimelineReject           TimelineType = "REJECT"              // 拒绝
+	TimelineAutoPass         TimelineType = "AUTO_PASS"           // 自动通过
+	TimelineAutoReject       TimelineType = "AUTO_REJECT"         // 自动拒绝
+	TimelineRemoveRepeat     TimelineType = "REMOVE_REPEAT"       // 去重
+	TimelineTransfer         TimelineType = "TRANSFER"            // 转交
+	TimelineAddApproverFront TimelineType = "ADD_APPROVER_BEFORE" // 前加签
+	TimelineAddApprover      TimelineType = "ADD_APPROVER"        // 并加签
+	TimelineAddApproverAfter TimelineType = "ADD_APPROVER_AFTER"  // 后加签
+	TimelineDeleteApprover   TimelineType = "DELETE_APPROVER"     // 减签
+	TimelineRollbackSelected TimelineType = "ROLLBACK_SELECTED"   // 指定回退
+	TimelineRollback         TimelineType = "ROLLBACK"            // 全部回退
+	TimelineCancel           TimelineType = "CANCEL"              // 撤回
+	TimelineDelete           TimelineType = "DELETE"              // 删除
+	TimelineCc               TimelineType = "CC"                  // 抄送
+)
+
+// Instance 解析后的审批实例，任务和动态按时间排序
+type Instance struct {
+	Code         string
+	ApprovalCode string
+	ApprovalName string
+	SerialNumber string
+	UserId       string
+	OpenId       string
+	DepartmentId string
+	Status       InstanceStatus
+	Reverted     bool // 通过后被撤销
+	StartTime    time.Time
+	EndTime      time.Time // 未结束时为零值
+	Form         []FormWidget
+	Tasks        []InstanceTask
+	Timeline     []TimelineEvent
+	Comments     []InstanceComment
+	Raw          *larkapproval.GetInstanceRespData
+}
+
+// InstanceTask 审批任务，自动通过、自动拒绝时 UserId 为空
+type InstanceTask struct {
+	Id           string
+	UserId       string
+	OpenId       string
+	Status       TaskStatus
+	NodeId       string
+	NodeName     string
+	CustomNodeId string
+	Type         string // 审批方式，如 AND、OR
+	StartTime    time.Time
+	EndTime      time.Time // 未完成时为零值
+}
+
+// TimelineEvent 一条审批动态，UserId 为操作人；
+// TargetUserIds 为抄送人、转交对象或加签、减签的审批人
+type TimelineEvent struct {
+	Type          TimelineType
+	Time          time.Time
+	UserId        string
+	OpenId        string
+	TaskId        string
+	NodeKey       string
+	Comment       string
+	TargetUserIds []string
+	TargetOpenIds []string
+}
+
+// InstanceComment 审批评论
+type InstanceComment struct {
+	Id      string
+	UserId  string
+	OpenId  string
+	Comment string
+	Time    time.Time
+}
+
+// NodeDuration 一个节点的一次审批耗时，被回退后再次进入同一节点会单独统计
+type NodeDuration struct {
+	NodeId   string
+	NodeName string
+	Start    time.Time
+	End      time.Time // 未完成时为零值
+	Duration time.Duration
+}
+
+// GetApprovalInstance 获取并解析审批实例
+func (c *larkClient) GetApprovalInstance(ctx context.Context, instCode string) (*Instance, error) {
+	data, err := c.GetApprovalInstById(ctx, instCode)
+	if err != nil {
+		return nil, err
+	}
+	return NewInstance(data)
+}
+
+// NewInstance 解析 GetApprovalInstById 的返回值
+func NewInstance(data *larkapproval.GetInstanceRespData) (*Instance, error) {
+	inst := &Instance{
+		Code:         larkcore.StringValue(data.InstanceCode),
+		ApprovalCode: larkcore.StringValue(data.ApprovalCode),
+		ApprovalName: larkcore.StringValue(data.ApprovalName),
+		SerialNumber: larkcore.StringValue(data.SerialNumber),
+		UserId:       larkcore.StringValue(data.UserId),
+		OpenId:       larkcore.StringValue(data.OpenId),
+		DepartmentId: larkcore.StringValue(data.DepartmentId),
+		Status:       InstanceStatus(larkcore.StringValue(data.Status)),
+		Reverted:     larkcore.BoolValue(data.Reverted),
+		StartTime:    parseMillis(larkcore.StringValue(data.StartTime)),
+		EndTime:      parseMillis(larkcore.StringValue(data.EndTime)),
+		Tasks:        make([]InstanceTask, 0, len(data.TaskList)),
+		Timeline:     make([]TimelineEvent, 0, len(data.Timeline)),
+		Comments:     make([]InstanceComment, 0, len(data.CommentList)),
+		Raw:          data,
+	}
+	if form := larkcore.StringValue(data.Form); form != "" {
+		widgets, err := ParseForm(form)
+		if err != nil {
+			return nil, err
+		}
+		inst.Form = widgets
+	}
+	for _, task := range data.TaskList {
+		inst.Tasks = append(inst.Tasks, InstanceTask{
+			Id:           larkcore.StringValue(task.Id),
+			UserId:       larkcore.StringValue(task.UserId),
+			OpenId:       larkcore.StringValue(task.OpenId),
+			Status:       TaskStatus(larkcore.StringValue(task.Status)),
+			NodeId:       larkcore.StringValue(task.NodeId),
+			NodeName:     larkcore.StringValue(task.NodeName),
+			CustomNodeId: larkcore.StringValue(task.CustomNodeId),
+			Type:         larkcore.StringValue(task.Type),
+			StartTime:    parseMillis(larkcore.StringValue(task.StartTime)),
+			EndTime:      parseMillis(larkcore.StringValue(task.EndTime)),
+		})
+	}
+	for _, item := range data.Timeline {
+		inst.Timeline = append(inst.Timeline, newTimelineEvent(item))
+	}
+	for _, comment := range data.CommentList {
+		inst.Comments = append(inst.Comments, InstanceComment{
+			Id:      larkcore.StringValue(comment.Id),
+			UserId:  larkcore.StringValue(comment.UserId),
+			OpenId:  larkcore.StringValue(comment.OpenId),
+			Comment: larkcore.StringValue(comment.Comment),
+			Time:    parseMillis(larkcore.StringValue(comment.CreateTime)),
+		})
+	}
+	sort.SliceStable(inst.Tasks, func(i, j int) bool {
+		return inst.Tasks[i].StartTime.Before(inst.Tasks[j].StartTime)
+	})
+	sort.SliceStable(inst.Timeline, func(i, j int) bool {
+		return inst.Timeline[i].Time.Before(inst.Timeline[j].Time)
+	})
+	return inst, nil
+}
+
+// newTimelineEvent 抄送人在 user_id_list 中，转交、加签、减签的对象在 ext 中
+func newTimelineEvent(item *larkapproval.InstanceTimeline) TimelineEvent {
+	e := TimelineEvent{
+		Type:          TimelineType(larkcore.StringValue(item.Type)),
+		Time:          parseMillis(larkcore.StringValue(item.CreateTime)),
+		UserId:        larkcore.StringValue(item.UserId),
+		OpenId:        larkcore.StringValue(item.OpenId),
+		TaskId:        larkcore.StringValue(item.TaskId),
+		NodeKey:       larkcore.StringValue(item.NodeKey),
+		Comment:       larkcore.StringValue(item.Comment),
+		TargetUserIds: item.UserIdList,
+		TargetOpenIds: item.OpenIdList,
+	}
+	var ext struct {
+		UserIdList []string `json:"user_id_list"`
+		UserId     string   `json:"user_id"`
+		OpenIdList []string `json:"open_id_list"`
+		OpenId     string   `json:"open_id"`
+	}
+	if s := larkcore.StringValue(item.Ext); s != "" && json.Unmarshal([]byte(s), &ext) == nil {
+		if len(e.TargetUserIds) == 0 {
+			e.TargetUserIds = ext.UserIdList
+			if len(e.TargetUserIds) == 0 && ext.UserId != "" {
+				e.TargetUserIds = []string{ext.UserId}
+			}
+		}
+		if len(e.TargetOpenIds) == 0 {
+			e.TargetOpenIds = ext.OpenIdList
+			if len(e.TargetOpenIds) == 0 && ext.OpenId != "" {
+				e.TargetOpenIds = []string{ext.OpenId}
+			}
+		}
+	}
+	return e
+}
+
+// parseMillis 解析毫秒时间戳，为空或 0 时返回零值
+func parseMillis(s string) time.Time {
+	ms, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || ms == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
+
+// IsFinished 实例是否已结束，包括通过、拒绝、撤回和删除
+func (inst *Instance) IsFinished() bool {
+	return inst.Status != InstPending
+}
+
+// PendingTasks 待处理的任务
+func (inst *Instance) PendingTasks() []InstanceTask {
+	res := make([]InstanceTask, 0)
+	for _, task := range inst.Tasks {
+		if task.Status == TaskPending {
+			res = append(res, task)
+		}
+	}
+	return res
+}
+
+// PendingTasksFor userId 待处理的任务
+func (inst *Instance) PendingTasksFor(userId string) []InstanceTask {
+	res := make([]InstanceTask, 0)
+	for _, task := range inst.PendingTasks() {
+		if task.UserId == userId {
+			res = append(res, task)
+		}
+	}
+	return res
+}
+
+// PendingNodes 当前所在节点的名称
+func (inst *Instance) PendingNodes() []string {
+	res := make([]string, 0)
+	for _, task := range inst.PendingTasks() {
+		if !contains(res, task.NodeName) {
+			res = append(res, task.NodeName)
+		}
+	}
+	return res
+}
+
+// PendingAssignees 当前待处理人的 user_id
+func (inst *Instance) PendingAssignees() []string {
+	res := make([]string, 0)
+	for _, task := range inst.PendingTasks() {
+		if task.UserId != "" && !contains(res, task.UserId) {
+			res = append(res, task.UserId)
+		}
+	}
+	return res
+}
+
+// LastActor 最近一条由人操作的动态，自动通过、去重等系统动态不计，没有时返回 false
+func (inst *Instance) LastActor() (TimelineEvent, bool) {
+	for i := len(inst.Timeline) - 1; i >= 0; i-- {
+		if inst.Timeline[i].UserId != "" {
+			return inst.Timeline[i], true
+		}
+	}
+	return TimelineEvent{}, false
+}
+
+// NodeDurations 按进入节点的顺序统计各节点的耗时，未完成的节点计算到 now，实例已结束时计算到实例的结束时间。
+// 同一节点的多个任务合并统计，回退后再次进入的节点作为新的一次
+func (inst *Instance) NodeDurations(now time.Time) []NodeDuration {
+	if inst.Status != InstPending && !inst.EndTime.IsZero() {
+		// 实例结束后未处理的任务不会再推进
+		now = inst.EndTime
+	}
+	rollbacks := make([]time.Time, 0)
+	for _, e := range inst.Timeline {
+		if e.Type == TimelineRollback || e.Type == TimelineRollbackSelected {
+			rollbacks = append(rollbacks, e.Time)
+		}
+	}
+	res := make([]NodeDuration, 0)
+	// 节点当前这次审批在 res 中的下标
+	open := make(map[string]int)
+	for _, task := range inst.Tasks {
+		i, ok := open[task.NodeId]
+		if ok && rolledBack(rollbacks, res[i].Start, task.StartTime) {
+			ok = false
+		}
+		if !ok {
+			res = append(res, NodeDuration{NodeId: task.NodeId, NodeName: task.NodeName, Start: task.StartTime, End: task.EndTime})
+			open[task.NodeId] = len(res) - 1
+			continue
+		}
+		if task.EndTime.IsZero() || res[i].End.IsZero() {
+			res[i].End = time.Time{}
+		} else if task.EndTime.After(res[i].End) {
+			res[i].End = task.EndTime
+		}
+	}
+	for i := range res {
+		end := res[i].End
+		if end.IsZero() {
+			end = now
+		}
+		res[i].Duration = end.Sub(res[i].Start)
+	}
+	return res
+}
+
+// rolledBack from 之后、to 之前（含）是否发生过回退
+func rolledBack(rollbacks []time.Time, from, to time.Time) bool {
+	for _, t := range rollbacks {
+		if t.After(from) && !t.After(to) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package lark_sdk
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+const defaultMaxDownloadSize = 100 << 20
+
+// AttachmentMeta 附件信息，FileName 已经过清理，可以直接作为文件名使用
+type AttachmentMeta struct {
+	FileName    string
+	ContentType string
+	Size        int64
+}
+
+// GetAttachment 下载人事（标准版）附件，返回的 io.ReadCloser 直接读取响应体，调用方负责关闭。
+// 超过 WithMaxDownloadSize 限制时返回 ErrFileTooLarge，响应头中没有文件大小时在读取过程中返回；
+// Size 为 -1 表示大小未知
+func (c *larkClient) GetAttachment(ctx context.Context, token string) (io.ReadCloser, *AttachmentMeta, error) {
+	resp, err := c.getStream(ctx, "GetAttachment", "/open-apis/ehr/v1/attachments/"+url.PathEscape(token), "token", token)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.ContentLength > c.maxDownloadSize {
+		resp.Body.Close()
+		return nil, nil, errors.Wrapf(ErrFileTooLarge, "GetAttachment token: %s, limit: %d", token, c.maxDownloadSize)
+	}
+	fileName := ""
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		fileName = params["filename"]
+	}
+	// 只预读开头用于识别文件类型
+	body := bufio.NewReaderSize(resp.Body, 512)
+	head, _ := body.Peek(512)
+	meta := &AttachmentMeta{
+		FileName:    sanitizeFileName(fileName, token),
+		ContentType: contentTypeOf(resp.Header, head),
+		Size:        resp.ContentLength,
+	}
+	return &readCloser{Reader: &limitedReader{r: body, n: c.maxDownloadSize}, Closer: resp.Body}, meta, nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// DownloadAttachmentTo 下载附件到 dir 目录，目录不存在时创建，同名文件存在时自动加序号，返回文件路径
+func (c *larkClient) DownloadAttachmentTo(ctx context.Context, token, dir string) (string, error) {
+	rc, meta, err := c.GetAttachment(ctx, token)
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+	return saveFile(dir, meta.FileName, rc)
+}
+
+// saveFile 在 dir 下新建 fileName 并写入 r，不会覆盖已有文件
+func saveFile(dir, fileName string, r io.Reader) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	ext := filepath.Ext(fileName)
+	base := strings.TrimSuffix(fileName, ext)
+	for i := 0; i < 1000; i++ {
+		name := fileName
+		if i > 0 {
+			name = base + "_" + strconv.Itoa(i) + ext
+		}
+		path := filepath.Join(dir, name)
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if _, err = io.Copy(f, r); err != nil {
+			f.Close()
+			os.Remove(path)
+			return "", err
+		}
+		if err = f.Close(); err != nil {
+			os.Remove(path)
+			return "", err
+		}
+		return path, nil
+	}
+	return "", errors.Errorf("saveFile: too many files named %s in %s", fileName, dir)
+}
+
+// sanitizeFileName 去掉服务端文件名中的目录和不可见字符，避免写到目标目录之外，为空时使用 fallback
+func sanitizeFileName(name, fallback string) string {
+	name = strings.ReplaceAll(name, "\\", "/")
+	name = name[strings.LastIndex(name, "/")+1:]
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || strings.ContainsRune(`<>:"|?*`, r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimLeft(strings.TrimSpace(name), ".")
+	if len(name) > 200 {
+		ext := filepath.Ext(name)
+		if len(ext) > 20 {
+			ext = ""
+		}
+		name = strings.ToValidUTF8(name[:200-len(ext)], "") + ext
+	}
+	if name == "" {
+		return sanitizeFileName(fallback, "attachment")
+	}
+	return name
+}
+
+func contentTypeOf(header http.Header, data []byte) string {
+	if ct := header.Get("Content-Type"); ct != "" && !strings.HasPrefix(ct, "application/octet-stream") {
+		return ct
+	}
+	return http.DetectContentType(data)
+}
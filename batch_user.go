@@ -0,0 +1,94 @@
+package lark_sdk
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	_slice "github.com/YueY4n9/gotools/slice"
+	larkcontact "github.com/larksuite/oapi-sdk-go/v3/service/contact/v3"
+)
+
+// PartialError 批量操作中部分请求失败，FailedIds 为失败请求涉及的 id，Errs 为各请求的错误
+type PartialError struct {
+	FailedIds []string
+	Errs      []error
+}
+
+func (e *PartialError) Error() string {
+	msgs := make([]string, 0, len(e.Errs))
+	for _, err := range e.Errs {
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("lark: %d ids failed: %s", len(e.FailedIds), strings.Join(msgs, "; "))
+}
+
+// Unwrap 支持 errors.Is(err, ErrRateLimited) 等判断
+func (e *PartialError) Unwrap() []error {
+	return e.Errs
+}
+
+// BatchGetUsers 批量获取用户，结果以输入 id 为 key，部门 id 为 department_id 类型。
+// 不存在或无权限的用户不在结果中；部分请求失败时返回已获取的结果和 *PartialError
+func (c *larkClient) BatchGetUsers(ctx context.Context, ids []string, idType string) (map[string]*larkcontact.User, error) {
+	res := make(map[string]*larkcontact.User)
+	missIds := make([]string, 0, len(ids))
+	for _, id := range _slice.RemoveDuplication(ids) {
+		if id == "" {
+			continue
+		}
+		if user, ok := cacheGet[*larkcontact.User](ctx, c, c.cacheKey("user", idType, DepartmentId, id)); ok {
+			res[id] = user
+		} else {
+			missIds = append(missIds, id)
+		}
+	}
+	var partialErr *PartialError
+	for _, chunk := range _slice.ChunkSlice(missIds, 50) {
+		users, err := c.batchGetUser(ctx, chunk, idType)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			if partialErr == nil {
+				partialErr = &PartialError{}
+			}
+			partialErr.FailedIds = append(partialErr.FailedIds, chunk...)
+			partialErr.Errs = append(partialErr.Errs, err)
+			continue
+		}
+		for _, user := range users {
+			if id := userIdOf(user, idType); id != "" {
+				res[id] = user
+				cacheSet(ctx, c, c.cacheKey("user", idType, DepartmentId, id), user)
+			}
+		}
+	}
+	if partialErr != nil {
+		return res, partialErr
+	}
+	return res, nil
+}
+
+// batchGetUser 批量获取用户，ids 最多 50 个，无权限或不存在的用户不在结果中
+func (c *larkClient) batchGetUser(ctx context.Context, ids []string, userIdType string) ([]*larkcontact.User, error) {
+	req := larkcontact.NewBatchUserReqBuilder().
+		UserIds(ids).
+		UserIdType(userIdType).
+		DepartmentIdType(DepartmentId).
+		Build()
+	resp, err := c.client.Contact.User.Batch(ctx, req)
+	if err != nil {
+		c.Alert(err)
+		return nil, err
+	}
+	if !resp.Success() {
+		err = newLarkError("batchGetUser", resp.ApiResp, resp.CodeError, "user_id_type", userIdType, "count", len(ids))
+		c.Alert(err)
+		return nil, err
+	}
+	if resp.Data == nil {
+		return nil, nil
+	}
+	return resp.Data.Items, nil
+}
@@ -0,0 +1,217 @@
+package lark_sdk
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/larksuite/oapi-sdk-go/v3/event/dispatcher"
+	larkcontact "github.com/larksuite/oapi-sdk-go/v3/service/contact/v3"
+)
+
+// Cache 通讯录查询结果的缓存，值为 json 序列化后的数据，
+// 实现方出错时按未命中处理即可，例如接入 redis
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration)
+	Delete(ctx context.Context, keys ...string)
+}
+
+type memoryCache struct {
+	maxEntries int
+	mu         sync.Mutex
+	ll         *list.List
+	items      map[string]*list.Element
+}
+
+type memoryCacheEntry struct {
+	key      string
+	value    []byte
+	expireAt time.Time
+}
+
+// NewMemoryCache 进程内 LRU 缓存，最多保存 maxEntries 条，0 表示不限制
+func NewMemoryCache(maxEntries int) Cache {
+	return &memoryCache{
+		maxEntries: maxEntries,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+func (m *memoryCache) Get(_ context.Context, key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ele, ok := m.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := ele.Value.(*memoryCacheEntry)
+	if !entry.expireAt.IsZero() && time.Now().After(entry.expireAt) {
+		m.ll.Remove(ele)
+		delete(m.items, key)
+		return nil, false
+	}
+	m.ll.MoveToFront(ele)
+	return entry.value, true
+}
+
+func (m *memoryCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var expireAt time.Time
+	if ttl > 0 {
+		expireAt = time.Now().Add(ttl)
+	}
+	if ele, ok := m.items[key]; ok {
+		entry := ele.Value.(*memoryCacheEntry)
+		entry.value = value
+		entry.expireAt = expireAt
+		m.ll.MoveToFront(ele)
+		return
+	}
+	m.items[key] = m.ll.PushFront(&memoryCacheEntry{key: key, value: value, expireAt: expireAt})
+	for m.maxEntries > 0 && m.ll.Len() > m.maxEntries {
+		oldest := m.ll.Back()
+		m.ll.Remove(oldest)
+		delete(m.items, oldest.Value.(*memoryCacheEntry).key)
+	}
+}
+
+func (m *memoryCache) Delete(_ context.Context, keys ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range keys {
+		if ele, ok := m.items[key]; ok {
+			m.ll.Remove(ele)
+			delete(m.items, key)
+		}
+	}
+}
+
+func (c *larkClient) cacheKey(parts ...string) string {
+	return "lark:" + c.appId + ":" + strings.Join(parts, ":")
+}
+
+func cacheGet[T any](ctx context.Context, c *larkClient, key string) (T, bool) {
+	var v T
+	if c.cache == nil {
+		return v, false
+	}
+	bs, ok := c.cache.Get(ctx, key)
+	if !ok {
+		return v, false
+	}
+	if err := json.Unmarshal(bs, &v); err != nil {
+		return v, false
+	}
+	return v, true
+}
+
+func cacheSet(ctx context.Context, c *larkClient, key string, v interface{}) {
+	if c.cache == nil {
+		return
+	}
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	c.cache.Set(ctx, key, bs, c.cacheTTL)
+}
+
+// InvalidateUser 删除用户的缓存，ids 可以是 user_id、open_id 或 union_id。
+// 邮箱、手机号到用户 id 的转换结果不会被删除，在缓存过期后刷新
+func (c *larkClient) InvalidateUser(ctx context.Context, ids ...string) {
+	if c.cache == nil {
+		return
+	}
+	keys := make([]string, 0)
+	for _, id := range ids {
+		for _, userIdType := range []string{UserId, OpenId, UnionId} {
+			for _, deptIdType := range []string{DepartmentId, OpenDepartmentId} {
+				keys = append(keys, c.cacheKey("user", userIdType, deptIdType, id))
+			}
+			for _, toType := range []string{UserId, OpenId, UnionId, Email, Mobile} {
+				keys = append(keys, c.cacheKey("user_id", userIdType, toType, id))
+			}
+		}
+		keys = append(keys, c.cacheKey("emp_name", id))
+	}
+	// 用户的直属上级可能变化
+	keys = append(keys, c.cacheKey("reports"))
+	c.cache.Delete(ctx, keys...)
+}
+
+// InvalidateDept 删除部门及其上级部门链的缓存，ids 可以是 department_id 或 open_department_id。
+// 子部门的上级部门链不会被删除，在缓存过期后刷新
+func (c *larkClient) InvalidateDept(ctx context.Context, ids ...string) {
+	if c.cache == nil {
+		return
+	}
+	keys := make([]string, 0)
+	for _, id := range ids {
+		for _, deptIdType := range []string{DepartmentId, OpenDepartmentId} {
+			keys = append(keys, c.cacheKey("dept", deptIdType, id), c.cacheKey("parent_dept", deptIdType, id))
+		}
+	}
+	c.cache.Delete(ctx, keys...)
+}
+
+// RegisterContactEvents 在 d 上注册用户、部门变更事件，收到事件时删除对应缓存。
+// 若调用方需要自行处理这些事件，不要调用本方法，在自己的 handler 中调用 InvalidateUser/InvalidateDept
+func (c *larkClient) RegisterContactEvents(d *dispatcher.EventDispatcher) *dispatcher.EventDispatcher {
+	invalidateUser := func(ctx context.Context, users ...*larkcontact.UserEvent) {
+		for _, user := range users {
+			if user == nil {
+				continue
+			}
+			c.InvalidateUser(ctx, nonEmpty(user.UserId, user.OpenId, user.UnionId)...)
+		}
+	}
+	invalidateDept := func(ctx context.Context, depts ...*larkcontact.DepartmentEvent) {
+		for _, dept := range depts {
+			if dept == nil {
+				continue
+			}
+			c.InvalidateDept(ctx, nonEmpty(dept.DepartmentId, dept.OpenDepartmentId)...)
+		}
+	}
+	return d.
+		OnP2UserUpdatedV3(func(ctx context.Context, event *larkcontact.P2UserUpdatedV3) error {
+			if event.Event != nil {
+				invalidateUser(ctx, event.Event.Object, event.Event.OldObject)
+			}
+			return nil
+		}).
+		OnP2UserDeletedV3(func(ctx context.Context, event *larkcontact.P2UserDeletedV3) error {
+			if event.Event != nil {
+				invalidateUser(ctx, event.Event.Object)
+			}
+			return nil
+		}).
+		OnP2DepartmentUpdatedV3(func(ctx context.Context, event *larkcontact.P2DepartmentUpdatedV3) error {
+			if event.Event != nil {
+				invalidateDept(ctx, event.Event.Object, event.Event.OldObject)
+			}
+			return nil
+		}).
+		OnP2DepartmentDeletedV3(func(ctx context.Context, event *larkcontact.P2DepartmentDeletedV3) error {
+			if event.Event != nil {
+				invalidateDept(ctx, event.Event.Object)
+			}
+			return nil
+		})
+}
+
+func nonEmpty(ptrs ...*string) []string {
+	res := make([]string, 0, len(ptrs))
+	for _, p := range ptrs {
+		if p != nil && *p != "" {
+			res = append(res, *p)
+		}
+	}
+	return res
+}
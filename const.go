@@ -4,6 +4,9 @@ const (
 	User             = "user"
 	UserId           = "user_id"
 	OpenId           = "open_id"
+	UnionId          = "union_id"
+	Email            = "email"
+	Mobile           = "mobile"
 	DepartmentId     = "department_id"
 	OpenDepartmentId = "open_department_id"
 )
@@ -0,0 +1,260 @@
+package lark_sdk
+
+import (
+	"context"
+
+	_slice "github.com/YueY4n9/gotools/slice"
+	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
+	larkcorehrv1 "github.com/larksuite/oapi-sdk-go/v3/service/corehr/v1"
+	larkcorehr "github.com/larksuite/oapi-sdk-go/v3/service/corehr/v2"
+)
+
+// coreHREmpFields 查询雇佣信息时默认返回的字段，为空时接口只返回 id
+var coreHREmpFields = []string{
+	"employee_number",
+	"employee_type_id",
+	"department_id",
+	"direct_manager_id",
+	"employment_status",
+	"effective_date",
+	"expiration_date",
+	"email_address",
+	"primary_contract_id",
+	"contract_end_date",
+	"probation_end_date",
+	"person_info",
+}
+
+// coreHRPreHireFields 查询待入职时默认返回的字段
+var coreHRPreHireFields = []string{
+	"person_info",
+	"employment_info",
+	"onboarding_info",
+}
+
+// BatchGetCoreHREmp 按 user_id 批量获取飞书人事的雇佣信息，部门 id 为 department_id 类型
+func (c *larkClient) BatchGetCoreHREmp(ctx context.Context, userIds []string) ([]*larkcorehr.Employee, error) {
+	res := make([]*larkcorehr.Employee, 0)
+	for _, chunk := range _slice.ChunkSlice(userIds, 100) {
+		req := larkcorehr.NewBatchGetEmployeeReqBuilder().
+			UserIdType(UserId).
+			DepartmentIdType(DepartmentId).
+			Body(&larkcorehr.BatchGetEmployeeReqBody{
+				Fields:        coreHREmpFields,
+				EmploymentIds: chunk,
+			}).
+			Build()
+		resp, err := c.client.Corehr.V2.Employee.BatchGet(ctx, req)
+		if err != nil {
+			c.Alert(err)
+			return nil, err
+		}
+		if !resp.Success() {
+			err = newLarkError("BatchGetCoreHREmp", resp.ApiResp, resp.CodeError, "count", len(chunk))
+			c.Alert(err)
+			return nil, err
+		}
+		if resp.Data == nil {
+			err = newEmptyDataError("BatchGetCoreHREmp", resp.ApiResp, "count", len(chunk))
+			c.Alert(err)
+			return nil, err
+		}
+		res = append(res, resp.Data.Items...)
+	}
+	return res, nil
+}
+
+// SearchCoreHREmpPager 搜索飞书人事的雇佣信息，body.Fields 为空时使用默认字段
+func (c *larkClient) SearchCoreHREmpPager(body *larkcorehr.SearchEmployeeReqBody) *Pager[*larkcorehr.Employee] {
+	// 复制一份，不修改调用方的 body
+	b := larkcorehr.SearchEmployeeReqBody{}
+	if body != nil {
+		b = *body
+	}
+	if len(b.Fields) == 0 {
+		b.Fields = coreHREmpFields
+	}
+	body = &b
+	return NewPager(func(ctx context.Context, pageToken string) (*Page[*larkcorehr.Employee], error) {
+		req := larkcorehr.NewSearchEmployeeReqBuilder().
+			PageSize(100).
+			PageToken(pageToken).
+			UserIdType(UserId).
+			DepartmentIdType(DepartmentId).
+			Body(body).
+			Build()
+		resp, err := c.client.Corehr.V2.Employee.Search(ctx, req)
+		if err != nil {
+			c.Alert(err)
+			return nil, err
+		}
+		if !resp.Success() {
+			err = newLarkError("SearchCoreHREmp", resp.ApiResp, resp.CodeError, "page_token", pageToken)
+			c.Alert(err)
+			return nil, err
+		}
+		if resp.Data == nil {
+			err = newEmptyDataError("SearchCoreHREmp", resp.ApiResp, "page_token", pageToken)
+			c.Alert(err)
+			return nil, err
+		}
+		return &Page[*larkcorehr.Employee]{
+			Items:     resp.Data.Items,
+			PageToken: larkcore.StringValue(resp.Data.PageToken),
+			HasMore:   larkcore.BoolValue(resp.Data.HasMore),
+		}, nil
+	})
+}
+
+// ListJobData 员工当前生效的任职信息
+func (c *larkClient) ListJobData(ctx context.Context, userIds []string) ([]*larkcorehr.EmployeeJobData, error) {
+	res := make([]*larkcorehr.EmployeeJobData, 0)
+	for _, chunk := range _slice.ChunkSlice(userIds, 100) {
+		req := larkcorehr.NewBatchGetEmployeesJobDataReqBuilder().
+			UserIdType(UserId).
+			DepartmentIdType(DepartmentId).
+			Body(&larkcorehr.BatchGetEmployeesJobDataReqBody{
+				EmploymentIds: chunk,
+			}).
+			Build()
+		resp, err := c.client.Corehr.V2.EmployeesJobData.BatchGet(ctx, req)
+		if err != nil {
+			c.Alert(err)
+			return nil, err
+		}
+		if !resp.Success() {
+			err = newLarkError("ListJobData", resp.ApiResp, resp.CodeError, "count", len(chunk))
+			c.Alert(err)
+			return nil, err
+		}
+		if resp.Data == nil {
+			err = newEmptyDataError("ListJobData", resp.ApiResp, "count", len(chunk))
+			c.Alert(err)
+			return nil, err
+		}
+		res = append(res, resp.Data.Items...)
+	}
+	return res, nil
+}
+
+// ListContract 员工的全部合同
+func (c *larkClient) ListContract(ctx context.Context, userIds []string) ([]*larkcorehr.Contract, error) {
+	res := make([]*larkcorehr.Contract, 0)
+	for _, chunk := range _slice.ChunkSlice(userIds, 100) {
+		contracts, err := c.searchContractPager(chunk).Collect(ctx)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, contracts...)
+	}
+	return res, nil
+}
+
+func (c *larkClient) searchContractPager(userIds []string) *Pager[*larkcorehr.Contract] {
+	return NewPager(func(ctx context.Context, pageToken string) (*Page[*larkcorehr.Contract], error) {
+		req := larkcorehr.NewSearchContractReqBuilder().
+			PageSize(100).
+			PageToken(pageToken).
+			UserIdType(UserId).
+			Body(&larkcorehr.SearchContractReqBody{
+				EmploymentIdList: userIds,
+			}).
+			Build()
+		resp, err := c.client.Corehr.V2.Contract.Search(ctx, req)
+		if err != nil {
+			c.Alert(err)
+			return nil, err
+		}
+		if !resp.Success() {
+			err = newLarkError("ListContract", resp.ApiResp, resp.CodeError, "count", len(userIds))
+			c.Alert(err)
+			return nil, err
+		}
+		if resp.Data == nil {
+			err = newEmptyDataError("ListContract", resp.ApiResp, "count", len(userIds))
+			c.Alert(err)
+			return nil, err
+		}
+		return &Page[*larkcorehr.Contract]{
+			Items:     resp.Data.Items,
+			PageToken: larkcore.StringValue(resp.Data.PageToken),
+			HasMore:   larkcore.BoolValue(resp.Data.HasMore),
+		}, nil
+	})
+}
+
+// SearchPreHirePager 搜索待入职人员，body.Fields 为空时使用默认字段
+func (c *larkClient) SearchPreHirePager(body *larkcorehr.SearchPreHireReqBody) *Pager[*larkcorehr.PreHire] {
+	b := larkcorehr.SearchPreHireReqBody{}
+	if body != nil {
+		b = *body
+	}
+	if len(b.Fields) == 0 {
+		b.Fields = coreHRPreHireFields
+	}
+	body = &b
+	return NewPager(func(ctx context.Context, pageToken string) (*Page[*larkcorehr.PreHire], error) {
+		req := larkcorehr.NewSearchPreHireReqBuilder().
+			PageSize(100).
+			PageToken(pageToken).
+			UserIdType(UserId).
+			DepartmentIdType(DepartmentId).
+			Body(body).
+			Build()
+		resp, err := c.client.Corehr.V2.PreHire.Search(ctx, req)
+		if err != nil {
+			c.Alert(err)
+			return nil, err
+		}
+		if !resp.Success() {
+			err = newLarkError("SearchPreHire", resp.ApiResp, resp.CodeError, "page_token", pageToken)
+			c.Alert(err)
+			return nil, err
+		}
+		if resp.Data == nil {
+			err = newEmptyDataError("SearchPreHire", resp.ApiResp, "page_token", pageToken)
+			c.Alert(err)
+			return nil, err
+		}
+		return &Page[*larkcorehr.PreHire]{
+			Items:     resp.Data.Items,
+			PageToken: larkcore.StringValue(resp.Data.PageToken),
+			HasMore:   larkcore.BoolValue(resp.Data.HasMore),
+		}, nil
+	})
+}
+
+// SearchOffboardingPager 搜索离职信息，body 为空时查询全部
+func (c *larkClient) SearchOffboardingPager(body *larkcorehrv1.SearchOffboardingReqBody) *Pager[*larkcorehrv1.Offboarding] {
+	if body == nil {
+		body = &larkcorehrv1.SearchOffboardingReqBody{}
+	}
+	return NewPager(func(ctx context.Context, pageToken string) (*Page[*larkcorehrv1.Offboarding], error) {
+		req := larkcorehrv1.NewSearchOffboardingReqBuilder().
+			PageSize(100).
+			PageToken(pageToken).
+			UserIdType(UserId).
+			Body(body).
+			Build()
+		resp, err := c.client.Corehr.Offboarding.Search(ctx, req)
+		if err != nil {
+			c.Alert(err)
+			return nil, err
+		}
+		if !resp.Success() {
+			err = newLarkError("SearchOffboarding", resp.ApiResp, resp.CodeError, "page_token", pageToken)
+			c.Alert(err)
+			return nil, err
+		}
+		if resp.Data == nil {
+			err = newEmptyDataError("SearchOffboarding", resp.ApiResp, "page_token", pageToken)
+			c.Alert(err)
+			return nil, err
+		}
+		return &Page[*larkcorehrv1.Offboarding]{
+			Items:     resp.Data.Items,
+			PageToken: larkcore.StringValue(resp.Data.PageToken),
+			HasMore:   larkcore.BoolValue(resp.Data.HasMore),
+		}, nil
+	})
+}
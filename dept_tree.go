@@ -0,0 +1,210 @@
+package lark_sdk
+
+import (
+	"context"
+	"strings"
+
+	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
+	larkcontact "github.com/larksuite/oapi-sdk-go/v3/service/contact/v3"
+)
+
+const rootDeptId = "0"
+
+type DeptNode struct {
+	Dept     *larkcontact.Department
+	Parent   *DeptNode
+	Children []*DeptNode
+}
+
+func (n *DeptNode) Id() string {
+	return larkcore.StringValue(n.Dept.DepartmentId)
+}
+
+func (n *DeptNode) Name() string {
+	return larkcore.StringValue(n.Dept.Name)
+}
+
+// DeptTree 部门树，方法中的部门 id 可以是 department_id 或 open_department_id
+type DeptTree struct {
+	Root    *DeptNode
+	nodes   map[string]*DeptNode
+	openIds map[string]string
+}
+
+// BuildDeptTree 拉取全部部门并构建部门树
+func (c *larkClient) BuildDeptTree(ctx context.Context) (*DeptTree, error) {
+	depts, err := c.ListChildDeptByDeptId(ctx, DepartmentId, rootDeptId)
+	if err != nil {
+		return nil, err
+	}
+	return NewDeptTree(depts), nil
+}
+
+// NewDeptTree 由 department_id 类型的部门列表构建部门树，列表中应包含根部门 "0"，
+// 找不到父部门或父子关系成环的部门挂在根部门下
+func NewDeptTree(depts []*larkcontact.Department) *DeptTree {
+	t := &DeptTree{
+		nodes:   make(map[string]*DeptNode),
+		openIds: make(map[string]string),
+	}
+	for _, dept := range depts {
+		if dept == nil || dept.DepartmentId == nil {
+			continue
+		}
+		t.nodes[*dept.DepartmentId] = &DeptNode{Dept: dept}
+		if dept.OpenDepartmentId != nil {
+			t.openIds[*dept.OpenDepartmentId] = *dept.DepartmentId
+		}
+	}
+	root, ok := t.nodes[rootDeptId]
+	if !ok {
+		root = &DeptNode{Dept: &larkcontact.Department{DepartmentId: larkcore.StringPtr(rootDeptId)}}
+		t.nodes[rootDeptId] = root
+	}
+	t.Root = root
+	for _, dept := range depts {
+		if dept == nil || dept.DepartmentId == nil || *dept.DepartmentId == rootDeptId {
+			continue
+		}
+		node := t.nodes[*dept.DepartmentId]
+		if node.Parent != nil {
+			continue
+		}
+		parent, ok := t.nodes[larkcore.StringValue(dept.ParentDepartmentId)]
+		if !ok || isAncestorOrSelf(node, parent) {
+			parent = root
+		}
+		node.Parent = parent
+		parent.Children = append(parent.Children, node)
+	}
+	return t
+}
+
+// isAncestorOrSelf node 是否为 n 或 n 的上级，用于避免父子关系成环
+func isAncestorOrSelf(node, n *DeptNode) bool {
+	for p := n; p != nil; p = p.Parent {
+		if p == node {
+			return true
+		}
+	}
+	return false
+}
+
+// Node 查找部门节点，找不到时返回 nil
+func (t *DeptTree) Node(deptId string) *DeptNode {
+	if node, ok := t.nodes[deptId]; ok {
+		return node
+	}
+	if id, ok := t.openIds[deptId]; ok {
+		return t.nodes[id]
+	}
+	return nil
+}
+
+// ToDepartmentId open_department_id 转 department_id
+func (t *DeptTree) ToDepartmentId(openDeptId string) (string, bool) {
+	id, ok := t.openIds[openDeptId]
+	return id, ok
+}
+
+// ToOpenDepartmentId department_id 转 open_department_id
+func (t *DeptTree) ToOpenDepartmentId(deptId string) (string, bool) {
+	node, ok := t.nodes[deptId]
+	if !ok || node.Dept.OpenDepartmentId == nil {
+		return "", false
+	}
+	return *node.Dept.OpenDepartmentId, true
+}
+
+// Ancestors 从根部门到直属上级的部门列表，不包含自身
+func (t *DeptTree) Ancestors(deptId string) []*larkcontact.Department {
+	node := t.Node(deptId)
+	if node == nil {
+		return nil
+	}
+	res := make([]*larkcontact.Department, 0)
+	for p := node.Parent; p != nil; p = p.Parent {
+		res = append(res, p.Dept)
+	}
+	for i, j := 0, len(res)-1; i < j; i, j = i+1, j-1 {
+		res[i], res[j] = res[j], res[i]
+	}
+	return res
+}
+
+// Descendants 所有下级部门，先序遍历，不包含自身
+func (t *DeptTree) Descendants(deptId string) []*larkcontact.Department {
+	node := t.Node(deptId)
+	if node == nil {
+		return nil
+	}
+	res := make([]*larkcontact.Department, 0)
+	var walk func(n *DeptNode)
+	walk = func(n *DeptNode) {
+		for _, child := range n.Children {
+			res = append(res, child.Dept)
+			walk(child)
+		}
+	}
+	walk(node)
+	return res
+}
+
+// Depth 根部门为 0，找不到时返回 -1
+func (t *DeptTree) Depth(deptId string) int {
+	node := t.Node(deptId)
+	if node == nil {
+		return -1
+	}
+	depth := 0
+	for p := node.Parent; p != nil; p = p.Parent {
+		depth++
+	}
+	return depth
+}
+
+// Path 以 / 连接的部门名称路径，如 "公司/研发部/后端组"，根部门没有名称时省略
+func (t *DeptTree) Path(deptId string) string {
+	node := t.Node(deptId)
+	if node == nil {
+		return ""
+	}
+	names := make([]string, 0)
+	for _, dept := range t.Ancestors(deptId) {
+		if name := larkcore.StringValue(dept.Name); name != "" {
+			names = append(names, name)
+		}
+	}
+	names = append(names, node.Name())
+	return strings.Join(names, "/")
+}
+
+// LowestCommonAncestor 两个部门最近的公共上级（可以是其中之一）的 department_id
+func (t *DeptTree) LowestCommonAncestor(deptId1, deptId2 string) (string, bool) {
+	n1, n2 := t.Node(deptId1), t.Node(deptId2)
+	if n1 == nil || n2 == nil {
+		return "", false
+	}
+	seen := make(map[*DeptNode]bool)
+	for p := n1; p != nil; p = p.Parent {
+		seen[p] = true
+	}
+	for p := n2; p != nil; p = p.Parent {
+		if seen[p] {
+			return p.Id(), true
+		}
+	}
+	return "", false
+}
+
+// MemberCount 部门及下级部门的用户数，primary 为 true 时只统计主属用户
+func (t *DeptTree) MemberCount(deptId string, primary bool) int {
+	node := t.Node(deptId)
+	if node == nil {
+		return 0
+	}
+	if primary {
+		return larkcore.IntValue(node.Dept.PrimaryMemberCount)
+	}
+	return larkcore.IntValue(node.Dept.MemberCount)
+}
@@ -0,0 +1,214 @@
+package lark_sdk
+
+import (
+	"context"
+	"strconv"
+
+	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
+	larkcorehr "github.com/larksuite/oapi-sdk-go/v3/service/corehr/v2"
+	larkehr "github.com/larksuite/oapi-sdk-go/v3/service/ehr/v1"
+)
+
+// EmployeeStatus 员工状态，屏蔽人事（标准版）与飞书人事的差异
+type EmployeeStatus string
+
+const (
+	EmpPreHire        EmployeeStatus = "pre_hire"         // 待入职
+	EmpActive         EmployeeStatus = "active"           // 在职
+	EmpToBeOffboarded EmployeeStatus = "to_be_offboarded" // 待离职
+	EmpOffboarded     EmployeeStatus = "offboarded"       // 已离职
+	EmpHireCancelled  EmployeeStatus = "hire_cancelled"   // 已取消入职
+)
+
+// Employee 统一的员工信息，日期格式为 2006-01-02，DepartmentId 统一为 department_id
+// （人事（标准版）无法转换时为 open_department_id），
+// UserId、DirectManagerId 为 user_id。EmployeeType 与数据来源有关，见字段说明
+type Employee struct {
+	UserId          string         `json:"user_id"`
+	EmployeeNumber  string         `json:"employee_number"`
+	Name            string         `json:"name"`
+	Email           string         `json:"email"`
+	DepartmentId    string         `json:"department_id"`
+	DirectManagerId string         `json:"direct_manager_id"`
+	EmployeeType    string         `json:"employee_type"` // 人事（标准版）为雇员类型编号，如 "1" 正式、"2" 实习；飞书人事为人员类型 ID
+	Status          EmployeeStatus `json:"status"`
+	HireDate        string         `json:"hire_date"`             // 入职日期，飞书人事为当前雇佣记录的生效日期，再入职的员工为最近一次入职日期
+	LeaveDate       string         `json:"leave_date"`            // 离职日期，即最后一个工作日，未确定离职时为空
+	PreHireId       string         `json:"pre_hire_id,omitempty"` // 飞书人事的待入职 id，此时 UserId 为空
+
+	CoreHR  *larkcorehr.Employee `json:"core_hr,omitempty"`
+	PreHire *larkcorehr.PreHire  `json:"pre_hire,omitempty"`
+	Ehr     *larkehr.Employee    `json:"ehr,omitempty"`
+}
+
+// ehrStatus 人事（标准版）的员工状态：1 待入职，2 在职，3 已取消入职，4 待离职，5 已离职
+var ehrStatus = map[int]EmployeeStatus{
+	1: EmpPreHire,
+	2: EmpActive,
+	3: EmpHireCancelled,
+	4: EmpToBeOffboarded,
+	5: EmpOffboarded,
+}
+
+func newEmployeeFromEhr(emp *larkehr.Employee) *Employee {
+	e := &Employee{
+		UserId: larkcore.StringValue(emp.UserId),
+		Ehr:    emp,
+	}
+	f := emp.SystemFields
+	if f == nil {
+		return e
+	}
+	e.EmployeeNumber = larkcore.StringValue(f.EmployeeNo)
+	e.Name = larkcore.StringValue(f.Name)
+	e.Email = larkcore.StringValue(f.Email)
+	// 人事（标准版）返回的是 open_department_id，由 ehrDeptIdConverter 转换
+	e.DepartmentId = larkcore.StringValue(f.DepartmentId)
+	if f.Manager != nil {
+		e.DirectManagerId = larkcore.StringValue(f.Manager.UserId)
+	}
+	if f.EmployeeType != nil {
+		e.EmployeeType = strconv.Itoa(*f.EmployeeType)
+	}
+	e.Status = ehrStatus[larkcore.IntValue(f.Status)]
+	e.HireDate = larkcore.StringValue(f.HireDate)
+	e.LeaveDate = larkcore.StringValue(f.LastDay)
+	return e
+}
+
+func newEmployeeFromCoreHR(emp *larkcorehr.Employee) *Employee {
+	e := &Employee{
+		UserId:          larkcore.StringValue(emp.EmploymentId),
+		EmployeeNumber:  larkcore.StringValue(emp.EmployeeNumber),
+		Email:           larkcore.StringValue(emp.EmailAddress),
+		DepartmentId:    larkcore.StringValue(emp.DepartmentId),
+		DirectManagerId: larkcore.StringValue(emp.DirectManagerId),
+		EmployeeType:    larkcore.StringValue(emp.EmployeeTypeId),
+		HireDate:        larkcore.StringValue(emp.EffectiveDate),
+		LeaveDate:       larkcore.StringValue(emp.ExpirationDate),
+		CoreHR:          emp,
+	}
+	if emp.PersonInfo != nil {
+		e.Name = personName(emp.PersonInfo)
+	}
+	switch enumName(emp.EmploymentStatus) {
+	case "hired":
+		e.Status = EmpActive
+		if e.LeaveDate != "" {
+			e.Status = EmpToBeOffboarded
+		}
+	case "terminated":
+		e.Status = EmpOffboarded
+	}
+	return e
+}
+
+func personName(p *larkcorehr.PersonInfo) string {
+	for _, name := range []*string{p.PreferredLocalFullName, p.PreferredName, p.LegalName, p.PreferredEnglishFullName} {
+		if v := larkcore.StringValue(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func enumName(e *larkcorehr.Enum) string {
+	if e == nil {
+		return ""
+	}
+	return larkcore.StringValue(e.EnumName)
+}
+
+// GetEmployee 获取员工信息，配置了 WithCoreHR 时从飞书人事获取，否则从人事（标准版）获取；
+// 员工不存在时返回 nil, nil
+func (c *larkClient) GetEmployee(ctx context.Context, userId string) (*Employee, error) {
+	employees, err := c.ListEmployees(ctx, []string{userId})
+	if err != nil {
+		return nil, err
+	}
+	if len(employees) == 0 {
+		return nil, nil
+	}
+	return employees[0], nil
+}
+
+// ListEmployees 按 user_id 批量获取员工信息，数据来源同 GetEmployee
+func (c *larkClient) ListEmployees(ctx context.Context, userIds []string) ([]*Employee, error) {
+	return c.listEmployees(ctx, userIds, true)
+}
+
+// listEmployees convertDept 为 false 时不转换人事（标准版）的部门 id，只需要姓名等信息时使用，避免额外的通讯录请求
+func (c *larkClient) listEmployees(ctx context.Context, userIds []string, convertDept bool) ([]*Employee, error) {
+	res := make([]*Employee, 0, len(userIds))
+	if c.useCoreHR {
+		employees, err := c.BatchGetCoreHREmp(ctx, userIds)
+		if err != nil {
+			return nil, err
+		}
+		for _, emp := range employees {
+			res = append(res, newEmployeeFromCoreHR(emp))
+		}
+		return res, nil
+	}
+	employees, err := c.getEmp(ctx, UserId, userIds)
+	if err != nil {
+		return nil, err
+	}
+	toDeptId := c.ehrDeptIdConverter()
+	for _, emp := range employees {
+		e := newEmployeeFromEhr(emp)
+		if convertDept {
+			toDeptId(ctx, e)
+		}
+		res = append(res, e)
+	}
+	return res, nil
+}
+
+// AllEmployees 全部在职和待离职员工，数据来源同 GetEmployee
+func (c *larkClient) AllEmployees(ctx context.Context) ([]*Employee, error) {
+	res := make([]*Employee, 0)
+	if c.useCoreHR {
+		pager := c.SearchCoreHREmpPager(&larkcorehr.SearchEmployeeReqBody{
+			EmploymentStatus: larkcore.StringPtr("hired"),
+		})
+		for emp, err := range pager.All(ctx) {
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, newEmployeeFromCoreHR(emp))
+		}
+		return res, nil
+	}
+	toDeptId := c.ehrDeptIdConverter()
+	err := c.StreamAllEmp(ctx, func(emp *larkehr.Employee) error {
+		e := newEmployeeFromEhr(emp)
+		toDeptId(ctx, e)
+		res = append(res, e)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ehrDeptIdConverter 把人事（标准版）员工的 open_department_id 转为 department_id，同一部门只查询一次。
+// 转换失败（如没有通讯录部门权限）时保留 open_department_id，不影响获取员工信息
+func (c *larkClient) ehrDeptIdConverter() func(ctx context.Context, e *Employee) {
+	deptIds := make(map[string]string)
+	return func(ctx context.Context, e *Employee) {
+		if e.DepartmentId == "" {
+			return
+		}
+		deptId, ok := deptIds[e.DepartmentId]
+		if !ok {
+			deptId = e.DepartmentId
+			if dept, err := c.GetDeptById(ctx, OpenDepartmentId, e.DepartmentId); err == nil && dept != nil && dept.DepartmentId != nil {
+				deptId = *dept.DepartmentId
+			}
+			deptIds[e.DepartmentId] = deptId
+		}
+		e.DepartmentId = deptId
+	}
+}
@@ -0,0 +1,262 @@
+package lark_sdk
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
+	larkcorehr "github.com/larksuite/oapi-sdk-go/v3/service/corehr/v2"
+	larkehr "github.com/larksuite/oapi-sdk-go/v3/service/ehr/v1"
+)
+
+const dateLayout = "2006-01-02"
+
+// EmployeeQuery 员工查询条件，各条件之间为「且」，同一条件的多个值之间为「或」
+type EmployeeQuery struct {
+	statuses  []EmployeeStatus
+	empTypes  []string
+	deptIds   []string
+	hireFrom  time.Time
+	hireTo    time.Time
+	leaveFrom time.Time
+	leaveTo   time.Time
+}
+
+// NewEmployeeQuery 默认查询在职和待离职员工
+func NewEmployeeQuery() *EmployeeQuery {
+	return &EmployeeQuery{statuses: []EmployeeStatus{EmpActive, EmpToBeOffboarded}}
+}
+
+// Status 员工状态
+func (q *EmployeeQuery) Status(statuses ...EmployeeStatus) *EmployeeQuery {
+	q.statuses = statuses
+	return q
+}
+
+// EmployeeType 人事（标准版）为雇员类型编号，如 "1" 正式、"2" 实习；飞书人事为人员类型 ID
+func (q *EmployeeQuery) EmployeeType(empTypes ...string) *EmployeeQuery {
+	q.empTypes = empTypes
+	return q
+}
+
+// Dept 所属部门的 department_id，包含下级部门
+func (q *EmployeeQuery) Dept(deptIds ...string) *EmployeeQuery {
+	q.deptIds = deptIds
+	return q
+}
+
+// HireDate 入职日期范围，按天比较，零值表示不限制
+func (q *EmployeeQuery) HireDate(from, to time.Time) *EmployeeQuery {
+	q.hireFrom, q.hireTo = from, to
+	return q
+}
+
+// LeaveDate 离职日期范围，按天比较，零值表示不限制
+func (q *EmployeeQuery) LeaveDate(from, to time.Time) *EmployeeQuery {
+	q.leaveFrom, q.leaveTo = from, to
+	return q
+}
+
+func (q *EmployeeQuery) hasStatus(status EmployeeStatus) bool {
+	for _, s := range q.statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func (q *EmployeeQuery) match(e *Employee) bool {
+	if len(q.statuses) > 0 && !q.hasStatus(e.Status) {
+		return false
+	}
+	if len(q.empTypes) > 0 && !contains(q.empTypes, e.EmployeeType) {
+		return false
+	}
+	return inDateRange(e.HireDate, q.hireFrom, q.hireTo) && inDateRange(e.LeaveDate, q.leaveFrom, q.leaveTo)
+}
+
+func inDateRange(date string, from, to time.Time) bool {
+	if from.IsZero() && to.IsZero() {
+		return true
+	}
+	if date == "" {
+		return false
+	}
+	if !from.IsZero() && date < from.Format(dateLayout) {
+		return false
+	}
+	if !to.IsZero() && date > to.Format(dateLayout) {
+		return false
+	}
+	return true
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// QueryEmployees 按条件查询员工，数据来源同 GetEmployee。
+// 服务端不支持的条件在本地过滤；飞书人事的待入职人员只按直属部门过滤
+func (c *larkClient) QueryEmployees(ctx context.Context, q *EmployeeQuery) ([]*Employee, error) {
+	if q == nil {
+		q = NewEmployeeQuery()
+	}
+	if c.useCoreHR {
+		return c.queryCoreHREmployees(ctx, q)
+	}
+	return c.queryEhrEmployees(ctx, q)
+}
+
+// ListNewHires since 当天及之后入职的员工
+func (c *larkClient) ListNewHires(ctx context.Context, since time.Time) ([]*Employee, error) {
+	return c.QueryEmployees(ctx, NewEmployeeQuery().
+		Status(EmpActive, EmpToBeOffboarded).
+		HireDate(since, time.Time{}))
+}
+
+// ListLeavers since 当天及之后离职的员工，包含已确定离职日期的待离职员工
+func (c *larkClient) ListLeavers(ctx context.Context, since time.Time) ([]*Employee, error) {
+	return c.QueryEmployees(ctx, NewEmployeeQuery().
+		Status(EmpToBeOffboarded, EmpOffboarded).
+		LeaveDate(since, time.Time{}))
+}
+
+func (c *larkClient) queryEhrEmployees(ctx context.Context, q *EmployeeQuery) ([]*Employee, error) {
+	status := make([]int, 0, len(q.statuses))
+	for code, s := range ehrStatus {
+		if q.hasStatus(s) {
+			status = append(status, code)
+		}
+	}
+	empTypes := make([]int, 0, len(q.empTypes))
+	for _, t := range q.empTypes {
+		if v, err := strconv.Atoi(t); err == nil {
+			empTypes = append(empTypes, v)
+		}
+	}
+	var deptIds map[string]bool
+	if len(q.deptIds) > 0 {
+		deptIds = make(map[string]bool)
+		for _, deptId := range q.deptIds {
+			depts, err := c.ListChildDeptByDeptId(ctx, DepartmentId, deptId)
+			if err != nil {
+				return nil, err
+			}
+			for _, dept := range depts {
+				deptIds[larkcore.StringValue(dept.DepartmentId)] = true
+			}
+		}
+	}
+	res := make([]*Employee, 0)
+	toDeptId := c.ehrDeptIdConverter()
+	err := streamPager(ctx, c.listEmpPager(status, empTypes), newProgressTracker(nil), func(emp *larkehr.Employee) error {
+		e := newEmployeeFromEhr(emp)
+		if !q.match(e) {
+			return nil
+		}
+		toDeptId(ctx, e)
+		if deptIds == nil || deptIds[e.DepartmentId] {
+			res = append(res, e)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *larkClient) queryCoreHREmployees(ctx context.Context, q *EmployeeQuery) ([]*Employee, error) {
+	res := make([]*Employee, 0)
+	all := len(q.statuses) == 0
+	if all || q.hasStatus(EmpPreHire) || q.hasStatus(EmpHireCancelled) {
+		body := &larkcorehr.SearchPreHireReqBody{
+			DepartmentIds:   q.deptIds,
+			EmployeeTypeIds: q.empTypes,
+		}
+		body.OnboardingDateStart, body.OnboardingDateEnd = dateRange(q.hireFrom, q.hireTo)
+		for preHire, err := range c.SearchPreHirePager(body).All(ctx) {
+			if err != nil {
+				return nil, err
+			}
+			if e := newEmployeeFromPreHire(preHire); e != nil && q.match(e) {
+				res = append(res, e)
+			}
+		}
+	}
+	hired := all || q.hasStatus(EmpActive) || q.hasStatus(EmpToBeOffboarded)
+	terminated := all || q.hasStatus(EmpOffboarded)
+	if !hired && !terminated {
+		return res, nil
+	}
+	body := &larkcorehr.SearchEmployeeReqBody{
+		DepartmentIdListIncludeSub: q.deptIds,
+	}
+	if !terminated {
+		body.EmploymentStatus = larkcore.StringPtr("hired")
+	} else if !hired {
+		body.EmploymentStatus = larkcore.StringPtr("terminated")
+	}
+	if len(q.empTypes) == 1 {
+		body.EmployeeTypeId = larkcore.StringPtr(q.empTypes[0])
+	}
+	body.EffectiveTimeStart, body.EffectiveTimeEnd = dateRange(q.hireFrom, q.hireTo)
+	for emp, err := range c.SearchCoreHREmpPager(body).All(ctx) {
+		if err != nil {
+			return nil, err
+		}
+		if e := newEmployeeFromCoreHR(emp); q.match(e) {
+			res = append(res, e)
+		}
+	}
+	return res, nil
+}
+
+// dateRange 转换为接口需要的起止日期，起止需要同时传入，都为零值时返回 nil
+func dateRange(from, to time.Time) (*string, *string) {
+	if from.IsZero() && to.IsZero() {
+		return nil, nil
+	}
+	start, end := "1900-01-01", "9999-12-31"
+	if !from.IsZero() {
+		start = from.Format(dateLayout)
+	}
+	if !to.IsZero() {
+		end = to.Format(dateLayout)
+	}
+	return &start, &end
+}
+
+// newEmployeeFromPreHire 待入职人员转为 Employee，已完成入职的返回 nil，以雇佣信息为准
+func newEmployeeFromPreHire(p *larkcorehr.PreHire) *Employee {
+	e := &Employee{Status: EmpPreHire}
+	if p.OnboardingInfo != nil {
+		switch larkcore.StringValue(p.OnboardingInfo.OnboardingStatus) {
+		case "completed":
+			return nil
+		case "withdrawn", "deleted":
+			e.Status = EmpHireCancelled
+		}
+		e.HireDate = larkcore.StringValue(p.OnboardingInfo.OnboardingDate)
+	}
+	if p.PersonInfo != nil {
+		e.Name = personName(p.PersonInfo)
+	}
+	if info := p.EmploymentInfo; info != nil {
+		e.EmployeeNumber = larkcore.StringValue(info.WorkerId)
+		e.Email = larkcore.StringValue(info.WorkEmail)
+		e.DepartmentId = larkcore.StringValue(info.DepartmentId)
+		e.DirectManagerId = larkcore.StringValue(info.DirectLeaderId)
+		e.EmployeeType = larkcore.StringValue(info.EmployeeTypeId)
+	}
+	e.PreHireId = larkcore.StringValue(p.PreHireId)
+	e.PreHire = p
+	return e
+}
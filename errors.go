@@ -0,0 +1,135 @@
+package lark_sdk
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrNotFound         = errors.New("lark: not found")
+	ErrPermissionDenied = errors.New("lark: permission denied")
+	ErrRateLimited      = errors.New("lark: rate limited")
+	ErrEmptyData        = errors.New("lark: empty response data")
+	ErrFileTooLarge     = errors.New("lark: file too large")
+	ErrWidgetType       = errors.New("lark: unexpected approval widget type")
+	ErrFormInvalid      = errors.New("lark: invalid approval form")
+	ErrDuplicateUuid    = errors.New("lark: duplicate approval instance uuid")
+)
+
+var (
+	notFoundCodes = map[int]bool{
+		1254004: true, // bitable 数据表不存在
+		1254043: true, // bitable 记录不存在
+		131005:  true, // wiki 节点不存在
+		1390002: true, // 审批定义不存在
+		1390003: true, // 审批实例不存在
+	}
+	permissionDeniedCodes = map[int]bool{
+		40004:    true, // 无部门权限
+		41050:    true, // 无用户权限
+		99991672: true, // 应用未开通所需权限
+		99991679: true, // 用户未授权所需权限
+		1254302:  true, // bitable 无访问权限
+	}
+	rateLimitedCodes = map[int]bool{
+		99991400: true, // 应用频率限制
+		1241001:  true, // ehr 频率限制
+	}
+)
+
+// LarkError 开放平台接口返回的业务错误
+type LarkError struct {
+	Code       int
+	Msg        string
+	LogId      string
+	HttpStatus int
+	Method     string
+	Args       map[string]interface{}
+}
+
+// newLarkError 由接口响应构造 LarkError，args 为 key, value 交替的关键参数
+func newLarkError(method string, apiResp *larkcore.ApiResp, codeErr larkcore.CodeError, args ...interface{}) *LarkError {
+	e := &LarkError{
+		Code:   codeErr.Code,
+		Msg:    codeErr.Msg,
+		Method: method,
+		Args:   make(map[string]interface{}),
+	}
+	if apiResp != nil {
+		e.LogId = apiResp.LogId()
+		e.HttpStatus = apiResp.StatusCode
+	}
+	if e.LogId == "" && codeErr.Err != nil {
+		e.LogId = codeErr.Err.LogID
+	}
+	for i := 0; i+1 < len(args); i += 2 {
+		e.Args[fmt.Sprint(args[i])] = args[i+1]
+	}
+	return e
+}
+
+// newEmptyDataError 接口返回成功但没有 data 时使用
+func newEmptyDataError(method string, apiResp *larkcore.ApiResp, args ...interface{}) *LarkError {
+	return newLarkError(method, apiResp, larkcore.CodeError{Msg: ErrEmptyData.Error()}, args...)
+}
+
+func (e *LarkError) Error() string {
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("lark: %s failed, code: %d, msg: %s", e.Method, e.Code, e.Msg))
+	if e.LogId != "" {
+		sb.WriteString(", log_id: " + e.LogId)
+	}
+	keys := make([]string, 0, len(e.Args))
+	for k := range e.Args {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		sb.WriteString(fmt.Sprintf(", %s: %v", k, e.Args[k]))
+	}
+	return sb.String()
+}
+
+// Is 使 errors.Is(err, ErrNotFound) 等判断可用
+func (e *LarkError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return notFoundCodes[e.Code] || e.HttpStatus == http.StatusNotFound
+	case ErrPermissionDenied:
+		return permissionDeniedCodes[e.Code] || e.HttpStatus == http.StatusForbidden
+	case ErrRateLimited:
+		return rateLimitedCodes[e.Code] || e.HttpStatus == http.StatusTooManyRequests
+	case ErrEmptyData:
+		return e.Code == 0
+	case ErrDuplicateUuid:
+		// 审批 v4 创建实例时 uuid 已存在
+		return e.Code == 1390018
+	}
+	return false
+}
+
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+func IsPermissionDenied(err error) bool {
+	return errors.Is(err, ErrPermissionDenied)
+}
+
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited)
+}
+
+// logIdOf 取 err 中的 log id，没有时返回空串
+func logIdOf(err error) string {
+	var larkErr *LarkError
+	if errors.As(err, &larkErr) {
+		return larkErr.LogId
+	}
+	return ""
+}
@@ -0,0 +1,307 @@
+package lark_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
+	"github.com/pkg/errors"
+)
+
+// WidgetDef 审批定义中的控件
+type WidgetDef struct {
+	ID       string         `json:"id"`
+	Name     string         `json:"name"`
+	Type     string         `json:"type"`
+	Required bool           `json:"required"`
+	Options  []WidgetOption `json:"-"`
+	Children []WidgetDef    `json:"children,omitempty"`
+
+	Option json.RawMessage `json:"option,omitempty"`
+}
+
+// WidgetOption 单选、多选控件的选项，Value 为创建实例时使用的 key
+type WidgetOption struct {
+	Value string `json:"value"`
+	Text  string `json:"text"`
+}
+
+// ParseWidgetDefs 解析审批定义的 form 字段
+func ParseWidgetDefs(formStr string) ([]WidgetDef, error) {
+	res := make([]WidgetDef, 0)
+	if err := json.Unmarshal([]byte(formStr), &res); err != nil {
+		return nil, err
+	}
+	parseWidgetOptions(res)
+	return res, nil
+}
+
+func parseWidgetOptions(defs []WidgetDef) {
+	for i := range defs {
+		// 单选、多选的 option 为选项数组，其他控件的 option 为配置，忽略
+		_ = json.Unmarshal(defs[i].Option, &defs[i].Options)
+		parseWidgetOptions(defs[i].Children)
+	}
+}
+
+// FormBuilder 按审批定义构建创建实例的表单，按控件名称赋值并在本地校验
+type FormBuilder struct {
+	defs   []WidgetDef
+	values map[string]interface{}
+	rows   map[string][]*FormRow
+	errs   []string
+}
+
+// FormRow 明细控件的一行
+type FormRow struct {
+	b      *FormBuilder
+	prefix string
+	defs   []WidgetDef
+	values map[string]interface{}
+}
+
+// NewFormBuilderByCode 拉取审批定义并创建 FormBuilder
+func (c *larkClient) NewFormBuilderByCode(ctx context.Context, approvalCode string) (*FormBuilder, error) {
+	define, err := c.GetApprovalDefineByCode(ctx, approvalCode)
+	if err != nil {
+		return nil, err
+	}
+	return NewFormBuilder(larkcore.StringValue(define.Form))
+}
+
+// NewFormBuilder 由审批定义的 form 字段创建 FormBuilder
+func NewFormBuilder(formStr string) (*FormBuilder, error) {
+	defs, err := ParseWidgetDefs(formStr)
+	if err != nil {
+		return nil, err
+	}
+	return &FormBuilder{
+		defs:   defs,
+		values: make(map[string]interface{}),
+		rows:   make(map[string][]*FormRow),
+	}, nil
+}
+
+func findWidgetDef(defs []WidgetDef, name string) *WidgetDef {
+	for i := range defs {
+		if defs[i].Name == name {
+			return &defs[i]
+		}
+	}
+	return nil
+}
+
+func (b *FormBuilder) errorf(format string, args ...interface{}) {
+	b.errs = append(b.errs, fmt.Sprintf(format, args...))
+}
+
+// Set 按控件名称赋值，值的类型：
+// 文本为 string；数字、金额为数值或数字字符串；日期为 time.Time 或 RFC3339 字符串；
+// 日期区间为 DateIntervalValue；单选为选项的 value 或 text，多选为它们的 []string；
+// 联系人为 user_id 的 []string；部门为 open_department_id 的 []string；
+// 附件、图片为 UploadApprovalFile 返回的 code 的 []string。明细控件使用 AddRow
+func (b *FormBuilder) Set(name string, value interface{}) *FormBuilder {
+	b.set(b.defs, b.values, "", name, value)
+	return b
+}
+
+// AddRow 给明细控件添加一行
+func (b *FormBuilder) AddRow(name string) *FormRow {
+	row := &FormRow{b: b, prefix: name, values: make(map[string]interface{})}
+	def := findWidgetDef(b.defs, name)
+	if def == nil {
+		b.errorf("%s: widget not found", name)
+		return row
+	}
+	if def.Type != WidgetFieldList {
+		b.errorf("%s: widget is %s, not %s", name, def.Type, WidgetFieldList)
+		return row
+	}
+	row.defs = def.Children
+	row.prefix = fmt.Sprintf("%s[%d]", name, len(b.rows[def.ID]))
+	b.rows[def.ID] = append(b.rows[def.ID], row)
+	return row
+}
+
+// Set 给明细行中的控件赋值，值的类型同 FormBuilder.Set
+func (r *FormRow) Set(name string, value interface{}) *FormRow {
+	if r.defs != nil {
+		r.b.set(r.defs, r.values, r.prefix+".", name, value)
+	}
+	return r
+}
+
+func (b *FormBuilder) set(defs []WidgetDef, values map[string]interface{}, prefix, name string, value interface{}) {
+	def := findWidgetDef(defs, name)
+	if def == nil {
+		b.errorf("%s%s: widget not found", prefix, name)
+		return
+	}
+	v, err := convertWidgetValue(def, value)
+	if err != nil {
+		b.errorf("%s%s: %v", prefix, name, err)
+		return
+	}
+	values[def.ID] = v
+}
+
+func convertWidgetValue(def *WidgetDef, value interface{}) (interface{}, error) {
+	switch def.Type {
+	case WidgetInput, WidgetTextarea:
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+	case WidgetNumber, WidgetAmount:
+		return toNumber(value)
+	case WidgetDate:
+		switch v := value.(type) {
+		case time.Time:
+			return v.Format(time.RFC3339), nil
+		case string:
+			if _, err := time.Parse(time.RFC3339, v); err != nil {
+				return nil, err
+			}
+			return v, nil
+		}
+	case WidgetDateInterval:
+		var v DateIntervalValue
+		switch d := value.(type) {
+		case DateIntervalValue:
+			v = d
+		case *DateIntervalValue:
+			v = *d
+		default:
+			return nil, errors.Errorf("want DateIntervalValue, got %T", value)
+		}
+		if v.End.Before(v.Start) {
+			return nil, errors.New("end is before start")
+		}
+		return map[string]interface{}{
+			"start":    v.Start.Format(time.RFC3339),
+			"end":      v.End.Format(time.RFC3339),
+			"interval": v.Interval,
+		}, nil
+	case WidgetRadio, WidgetRadioV2:
+		if s, ok := value.(string); ok {
+			return optionValue(def, s)
+		}
+	case WidgetCheckbox, WidgetCheckboxV2:
+		list, ok := toStringList(value)
+		if !ok {
+			break
+		}
+		res := make([]string, 0, len(list))
+		for _, s := range list {
+			v, err := optionValue(def, s)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, v)
+		}
+		return res, nil
+	case WidgetContact, WidgetAttachment, WidgetAttachmentV1, WidgetImage, WidgetImageV2:
+		if list, ok := toStringList(value); ok {
+			return list, nil
+		}
+	case WidgetDepartment:
+		list, ok := toStringList(value)
+		if !ok {
+			break
+		}
+		res := make([]map[string]string, 0, len(list))
+		for _, id := range list {
+			res = append(res, map[string]string{"open_id": id})
+		}
+		return res, nil
+	case WidgetFieldList:
+		return nil, errors.New("use AddRow for fieldList")
+	default:
+		return nil, errors.Errorf("unsupported widget type %s", def.Type)
+	}
+	return nil, errors.Errorf("unexpected value type %T for %s", value, def.Type)
+}
+
+func toNumber(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	case AmountValue:
+		return v.Value, nil
+	}
+	return 0, errors.Errorf("unexpected value type %T for number", value)
+}
+
+func toStringList(value interface{}) ([]string, bool) {
+	switch v := value.(type) {
+	case string:
+		return []string{v}, true
+	case []string:
+		return v, true
+	}
+	return nil, false
+}
+
+// optionValue 按 value 或 text 查找选项，返回选项的 value；定义中没有选项时原样返回
+func optionValue(def *WidgetDef, s string) (string, error) {
+	if len(def.Options) == 0 {
+		return s, nil
+	}
+	for _, option := range def.Options {
+		if option.Value == s || option.Text == s {
+			return option.Value, nil
+		}
+	}
+	return "", errors.Errorf("option %q not found", s)
+}
+
+// Build 校验必填项并生成表单，校验失败时返回 ErrFormInvalid，包含全部问题
+func (b *FormBuilder) Build() (json.RawMessage, error) {
+	errs := append([]string(nil), b.errs...)
+	form := b.build(b.defs, b.values, "", &errs)
+	if len(errs) > 0 {
+		return nil, errors.Wrap(ErrFormInvalid, strings.Join(errs, "; "))
+	}
+	return json.Marshal(form)
+}
+
+func (b *FormBuilder) build(defs []WidgetDef, values map[string]interface{}, prefix string, errs *[]string) []map[string]interface{} {
+	res := make([]map[string]interface{}, 0)
+	for _, def := range defs {
+		var value interface{}
+		if def.Type == WidgetFieldList && prefix == "" {
+			rows := make([][]map[string]interface{}, 0)
+			for _, row := range b.rows[def.ID] {
+				rows = append(rows, b.build(row.defs, row.values, row.prefix+".", errs))
+			}
+			if len(rows) > 0 {
+				value = rows
+			}
+		} else if v, ok := values[def.ID]; ok {
+			value = v
+		}
+		if value == nil {
+			if def.Required {
+				*errs = append(*errs, fmt.Sprintf("%s%s: required", prefix, def.Name))
+			}
+			continue
+		}
+		res = append(res, map[string]interface{}{
+			"id":    def.ID,
+			"type":  def.Type,
+			"value": value,
+		})
+	}
+	return res
+}
@@ -1,6 +1,6 @@
 module github.com/YueY4n9/lark-sdk
 
-go 1.21
+go 1.23
 
 toolchain go1.23.0
 
@@ -11,3 +11,8 @@ require (
 	github.com/larksuite/project-oapi-sdk-golang v1.0.24
 	github.com/pkg/errors v0.9.1
 )
+
+require (
+	github.com/gogo/protobuf v1.3.2 // indirect
+	github.com/gorilla/websocket v1.5.0 // indirect
+)
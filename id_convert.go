@@ -0,0 +1,167 @@
+package lark_sdk
+
+import (
+	"context"
+	"strings"
+
+	_slice "github.com/YueY4n9/gotools/slice"
+	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
+	larkcontact "github.com/larksuite/oapi-sdk-go/v3/service/contact/v3"
+	"github.com/pkg/errors"
+)
+
+// ConvertUserIds 批量转换用户 id，返回以输入 id 为 key 的结果，找不到或无权限的 id 不在结果中，
+// 部分请求失败时返回已转换的结果和 *PartialError。
+// fromType 可以是 UserId、OpenId、UnionId、Email、Mobile，toType 可以是 UserId、OpenId、UnionId，
+// fromType 为 UserId、OpenId、UnionId 时 toType 还可以是 Email、Mobile
+func (c *larkClient) ConvertUserIds(ctx context.Context, ids []string, fromType, toType string) (map[string]string, error) {
+	if !isUserIdType(fromType) && fromType != Email && fromType != Mobile {
+		return nil, errors.Errorf("ConvertUserIds: unsupported from type %q", fromType)
+	}
+	if !isUserIdType(toType) && !(isUserIdType(fromType) && (toType == Email || toType == Mobile)) {
+		return nil, errors.Errorf("ConvertUserIds: unsupported conversion from %q to %q", fromType, toType)
+	}
+	res := make(map[string]string)
+	missIds := make([]string, 0, len(ids))
+	for _, id := range _slice.RemoveDuplication(ids) {
+		if id == "" {
+			continue
+		}
+		if fromType == toType {
+			res[id] = id
+		} else if v, ok := cacheGet[string](ctx, c, c.cacheKey("user_id", fromType, toType, id)); ok {
+			res[id] = v
+		} else {
+			missIds = append(missIds, id)
+		}
+	}
+	if len(missIds) == 0 {
+		return res, nil
+	}
+	var fetched map[string]string
+	var err error
+	if fromType == Email || fromType == Mobile {
+		fetched, err = c.getUserIdByContact(ctx, missIds, fromType, toType)
+	} else {
+		fetched, err = c.convertUserIdByBatch(ctx, missIds, fromType, toType)
+	}
+	if fetched == nil {
+		return nil, err
+	}
+	for id, v := range fetched {
+		res[id] = v
+		cacheSet(ctx, c, c.cacheKey("user_id", fromType, toType, id), v)
+	}
+	return res, err
+}
+
+func (c *larkClient) convertUserIdByBatch(ctx context.Context, ids []string, fromType, toType string) (map[string]string, error) {
+	users, err := c.BatchGetUsers(ctx, ids, fromType)
+	if users == nil {
+		return nil, err
+	}
+	res := make(map[string]string)
+	for id, user := range users {
+		if to := userIdOf(user, toType); to != "" {
+			res[id] = to
+		}
+	}
+	return res, err
+}
+
+// getUserIdByContact 通过邮箱或手机号查询用户 id，包含离职用户，部分请求失败时返回已查询的结果和 *PartialError
+func (c *larkClient) getUserIdByContact(ctx context.Context, ids []string, fromType, toType string) (map[string]string, error) {
+	res := make(map[string]string)
+	inputs := make(map[string]string, len(ids))
+	for _, id := range ids {
+		inputs[normalizeContact(id)] = id
+	}
+	var partialErr *PartialError
+	for _, chunk := range _slice.ChunkSlice(ids, 50) {
+		infos, err := c.batchGetUserId(ctx, chunk, fromType, toType)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			if partialErr == nil {
+				partialErr = &PartialError{}
+			}
+			partialErr.FailedIds = append(partialErr.FailedIds, chunk...)
+			partialErr.Errs = append(partialErr.Errs, err)
+			continue
+		}
+		for _, info := range infos {
+			if info == nil || info.UserId == nil {
+				continue
+			}
+			key := larkcore.StringValue(info.Email)
+			if fromType == Mobile {
+				key = larkcore.StringValue(info.Mobile)
+			}
+			if id, ok := inputs[normalizeContact(key)]; ok {
+				res[id] = *info.UserId
+			}
+		}
+	}
+	if partialErr != nil {
+		return res, partialErr
+	}
+	return res, nil
+}
+
+// batchGetUserId 通过邮箱或手机号查询用户 id，ids 最多 50 个
+func (c *larkClient) batchGetUserId(ctx context.Context, ids []string, fromType, toType string) ([]*larkcontact.UserContactInfo, error) {
+	body := larkcontact.NewBatchGetIdUserReqBodyBuilder().IncludeResigned(true)
+	if fromType == Email {
+		body.Emails(ids)
+	} else {
+		body.Mobiles(ids)
+	}
+	req := larkcontact.NewBatchGetIdUserReqBuilder().
+		UserIdType(toType).
+		Body(body.Build()).
+		Build()
+	resp, err := c.client.Contact.User.BatchGetId(ctx, req)
+	if err != nil {
+		c.Alert(err)
+		return nil, err
+	}
+	if !resp.Success() {
+		err = newLarkError("ConvertUserIds", resp.ApiResp, resp.CodeError, "from_type", fromType, "to_type", toType, "count", len(ids))
+		c.Alert(err)
+		return nil, err
+	}
+	if resp.Data == nil {
+		return nil, nil
+	}
+	return resp.Data.UserList, nil
+}
+
+// normalizeContact 接口返回的邮箱、手机号格式可能与传入的不同，统一后再对应回输入
+func normalizeContact(s string) string {
+	s = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(s), " ", ""))
+	return strings.TrimPrefix(s, "+86")
+}
+
+func isUserIdType(idType string) bool {
+	return idType == UserId || idType == OpenId || idType == UnionId
+}
+
+func userIdOf(user *larkcontact.User, idType string) string {
+	if user == nil {
+		return ""
+	}
+	switch idType {
+	case UserId:
+		return larkcore.StringValue(user.UserId)
+	case OpenId:
+		return larkcore.StringValue(user.OpenId)
+	case UnionId:
+		return larkcore.StringValue(user.UnionId)
+	case Email:
+		return larkcore.StringValue(user.Email)
+	case Mobile:
+		return larkcore.StringValue(user.Mobile)
+	}
+	return ""
+}
@@ -0,0 +1,97 @@
+package lark_sdk
+
+import (
+	"time"
+
+	lark "github.com/larksuite/oapi-sdk-go/v3"
+	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
+)
+
+// Option 配置 larkClient，传给 NewClient
+type Option func(*larkClient)
+
+// Alerter 接收 SDK 调用过程中产生的错误
+type Alerter interface {
+	Alert(err error)
+}
+
+// WithDebugApp 设置用于发送告警、查询应用信息的调试应用
+func WithDebugApp(appId, appSecret string) Option {
+	return func(c *larkClient) {
+		c.debugId = appId
+		c.debugSecret = appSecret
+	}
+}
+
+// WithAdminUser 设置告警接收人，以及需要管理员身份的接口使用的 user_id
+func WithAdminUser(userId string) Option {
+	return func(c *larkClient) {
+		c.adminUserId = userId
+	}
+}
+
+// WithAlerter 替换默认的告警方式
+func WithAlerter(alerter Alerter) Option {
+	return func(c *larkClient) {
+		c.alerter = alerter
+	}
+}
+
+// WithHTTPClient 设置底层 lark.Client 使用的 http client
+func WithHTTPClient(httpClient larkcore.HttpClient) Option {
+	return func(c *larkClient) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithBaseURL 设置开放平台域名，如 lark.LarkBaseUrl
+func WithBaseURL(baseUrl string) Option {
+	return func(c *larkClient) {
+		c.baseUrl = baseUrl
+		c.larkOpts = append(c.larkOpts, lark.WithOpenBaseUrl(baseUrl))
+	}
+}
+
+// WithLogger 设置底层 lark.Client 使用的日志，告警发送失败时也记录到这里
+func WithLogger(logger larkcore.Logger, level larkcore.LogLevel) Option {
+	return func(c *larkClient) {
+		c.logger = logger
+		c.larkOpts = append(c.larkOpts, lark.WithLogger(logger), lark.WithLogLevel(level))
+	}
+}
+
+// WithRetryPolicy 设置失败重试及限流策略
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *larkClient) {
+		c.retryPolicy = policy
+	}
+}
+
+// WithConcurrency 设置按部门拉取用户时的并发数，默认串行
+func WithConcurrency(n int) Option {
+	return func(c *larkClient) {
+		c.concurrency = n
+	}
+}
+
+// WithCache 缓存用户、部门查询结果，ttl 为 0 时不过期
+func WithCache(cache Cache, ttl time.Duration) Option {
+	return func(c *larkClient) {
+		c.cache = cache
+		c.cacheTTL = ttl
+	}
+}
+
+// WithCoreHR GetEmployee、ListEmployees、AllEmployees、GetEmpNameMap 改为从飞书人事获取员工数据
+func WithCoreHR() Option {
+	return func(c *larkClient) {
+		c.useCoreHR = true
+	}
+}
+
+// WithMaxDownloadSize 下载文件的大小上限，默认 100MB
+func WithMaxDownloadSize(size int64) Option {
+	return func(c *larkClient) {
+		c.maxDownloadSize = size
+	}
+}
@@ -0,0 +1,126 @@
+package lark_sdk
+
+import (
+	"context"
+	"iter"
+)
+
+// Page 分页接口返回的一页数据
+type Page[T any] struct {
+	Items     []T
+	PageToken string
+	HasMore   bool
+}
+
+// PageFunc 按 pageToken 拉取一页数据，pageToken 为空表示第一页
+type PageFunc[T any] func(ctx context.Context, pageToken string) (*Page[T], error)
+
+// Pager 按需逐页拉取分页接口，不会一次性把全部数据加载到内存
+type Pager[T any] struct {
+	fetch     PageFunc[T]
+	pageToken string
+	maxItems  int
+	count     int
+	done      bool
+}
+
+func NewPager[T any](fetch PageFunc[T]) *Pager[T] {
+	return &Pager[T]{fetch: fetch}
+}
+
+// StartFrom 从 pageToken 指向的页开始拉取，用于中断后续传
+func (p *Pager[T]) StartFrom(pageToken string) *Pager[T] {
+	p.pageToken = pageToken
+	return p
+}
+
+// Limit 最多返回 maxItems 条数据，0 表示不限制
+func (p *Pager[T]) Limit(maxItems int) *Pager[T] {
+	p.maxItems = maxItems
+	return p
+}
+
+// PageToken 下一个未被完整消费的页的 token，传给 StartFrom 可以从该页继续
+func (p *Pager[T]) PageToken() string {
+	return p.pageToken
+}
+
+// Done 是否已经没有更多数据
+func (p *Pager[T]) Done() bool {
+	return p.done
+}
+
+// NextPage 拉取下一页，没有更多数据时返回 nil, nil
+func (p *Pager[T]) NextPage(ctx context.Context) ([]T, error) {
+	if p.done {
+		return nil, nil
+	}
+	page, truncated, err := p.fetchPage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.advance(page, truncated)
+	return page.Items, nil
+}
+
+// All 逐条返回数据，遇到错误时返回该错误并结束；提前 break 后可以通过 PageToken 续传
+func (p *Pager[T]) All(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for !p.done {
+			page, truncated, err := p.fetchPage(ctx)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range page.Items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			p.advance(page, truncated)
+		}
+	}
+}
+
+// Collect 拉取剩余全部数据
+func (p *Pager[T]) Collect(ctx context.Context) ([]T, error) {
+	res := make([]T, 0)
+	for item, err := range p.All(ctx) {
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, item)
+	}
+	return res, nil
+}
+
+// fetchPage 拉取当前页，truncated 表示因 Limit 只返回了该页的一部分
+func (p *Pager[T]) fetchPage(ctx context.Context) (*Page[T], bool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, false, err
+	}
+	page, err := p.fetch(ctx, p.pageToken)
+	if err != nil {
+		return nil, false, err
+	}
+	if page == nil {
+		page = &Page[T]{}
+	}
+	if p.maxItems > 0 && p.count+len(page.Items) > p.maxItems {
+		page.Items = page.Items[:p.maxItems-p.count]
+		return page, true, nil
+	}
+	return page, false, nil
+}
+
+func (p *Pager[T]) advance(page *Page[T], truncated bool) {
+	p.count += len(page.Items)
+	if truncated {
+		// 当前页没有消费完，PageToken 仍指向当前页，与 All 中提前 break 一致
+		p.done = true
+		return
+	}
+	p.pageToken = page.PageToken
+	p.done = !page.HasMore || page.PageToken == "" || (p.maxItems > 0 && p.count >= p.maxItems)
+}
@@ -2,11 +2,14 @@ package lark_sdk
 
 import (
 	"context"
+	"net/http"
+
+	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
 	projectsdk "github.com/larksuite/project-oapi-sdk-golang"
+	projectcore "github.com/larksuite/project-oapi-sdk-golang/core"
 	"github.com/larksuite/project-oapi-sdk-golang/service/project"
 	"github.com/larksuite/project-oapi-sdk-golang/service/task"
 	"github.com/larksuite/project-oapi-sdk-golang/service/workitem"
-	"net/http"
 )
 
 // user-key = 7288177041931763713
@@ -27,19 +30,23 @@ type projectClient struct {
 	client      *projectsdk.Client
 }
 
-func NewProjectClient(appId, appSecret string, debug ...string) ProjectClient {
+// NewProjectClient opts 中 WithDebugApp、WithHTTPClient、WithRetryPolicy 生效
+func NewProjectClient(appId, appSecret string, opts ...Option) ProjectClient {
+	cfg := &larkClient{retryPolicy: defaultRetryPolicy}
+	for _, opt := range opts {
+		opt(cfg)
+	}
 	header := http.Header{}
 	header.Add("X-USER-KEY", "7288177041931763713")
-	c := &projectClient{
-		appId:     appId,
-		appSecret: appSecret,
-		client:    projectsdk.NewClient(appId, appSecret, projectsdk.WithHeaders(header)),
-	}
-	if len(debug) == 2 {
-		c.debugId = debug[0]
-		c.debugSecret = debug[1]
+	return &projectClient{
+		appId:       appId,
+		appSecret:   appSecret,
+		debugId:     cfg.debugId,
+		debugSecret: cfg.debugSecret,
+		client: projectsdk.NewClient(appId, appSecret,
+			projectsdk.WithHeaders(header),
+			projectsdk.WithHttpClient(newRetryHttpClient(cfg.httpClient, cfg.retryPolicy))),
 	}
-	return c
 }
 
 func (c *projectClient) Client() *projectsdk.Client {
@@ -55,7 +62,7 @@ func (c *projectClient) ListProject(ctx context.Context, userKey string) ([]stri
 		return nil, err
 	}
 	if !resp.Success() {
-		return nil, resp
+		return nil, newProjectError("ListProject", resp.APIResp, resp.CodeError, "user_key", userKey)
 	}
 	return resp.Data, nil
 }
@@ -69,7 +76,7 @@ func (c *projectClient) ListWorkItem(ctx context.Context, projectKey string) ([]
 		return nil, err
 	}
 	if !resp.Success() {
-		return nil, resp
+		return nil, newProjectError("ListWorkItem", resp.APIResp, resp.CodeError, "project_key", projectKey)
 	}
 	return resp.Data, nil
 }
@@ -82,7 +89,24 @@ func (c *projectClient) ListTask(ctx context.Context) ([]*task.SubDetail, error)
 		return nil, err
 	}
 	if !resp.Success() {
-		return nil, resp
+		return nil, newProjectError("ListTask", resp.APIResp, resp.CodeError)
 	}
 	return resp.Data, nil
 }
+
+// newProjectError 把项目管理 SDK 的错误转为 LarkError
+func newProjectError(method string, apiResp *projectcore.APIResp, codeErr projectcore.CodeError, args ...interface{}) *LarkError {
+	code, msg := codeErr.ErrCode, codeErr.ErrMsg
+	if code == 0 {
+		code, msg = codeErr.Err.Code, codeErr.Err.Msg
+	}
+	var resp *larkcore.ApiResp
+	if apiResp != nil {
+		resp = &larkcore.ApiResp{StatusCode: apiResp.StatusCode, Header: apiResp.Header, RawBody: apiResp.RawBody}
+	}
+	e := newLarkError(method, resp, larkcore.CodeError{Code: code, Msg: msg}, args...)
+	if e.LogId == "" {
+		e.LogId = codeErr.Err.LogID
+	}
+	return e
+}
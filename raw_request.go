@@ -0,0 +1,115 @@
+package lark_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
+	"github.com/pkg/errors"
+)
+
+// defaultDownloadTimeout 未配置 WithHTTPClient 时下载文件的超时时间
+const defaultDownloadTimeout = 10 * time.Minute
+
+var defaultDownloadClient = &http.Client{Timeout: defaultDownloadTimeout}
+
+// tenantTokenCache 直接发起 http 请求时使用的 tenant_access_token
+type tenantTokenCache struct {
+	mu       sync.Mutex
+	token    string
+	expireAt time.Time
+}
+
+// tenantAccessToken 获取 tenant_access_token，过期前 3 分钟刷新，错误由调用方告警
+func (c *larkClient) tenantAccessToken(ctx context.Context) (string, error) {
+	c.tenantToken.mu.Lock()
+	defer c.tenantToken.mu.Unlock()
+	if c.tenantToken.token != "" && time.Now().Before(c.tenantToken.expireAt) {
+		return c.tenantToken.token, nil
+	}
+	resp, err := c.client.GetTenantAccessTokenBySelfBuiltApp(ctx, &larkcore.SelfBuiltTenantAccessTokenReq{
+		AppID:     c.appId,
+		AppSecret: c.appSecret,
+	})
+	if err != nil {
+		return "", err
+	}
+	if !resp.Success() {
+		return "", newLarkError("tenantAccessToken", resp.ApiResp, resp.CodeError)
+	}
+	c.tenantToken.token = resp.TenantAccessToken
+	c.tenantToken.expireAt = time.Now().Add(time.Duration(resp.Expire)*time.Second - 3*time.Minute)
+	return resp.TenantAccessToken, nil
+}
+
+// downloadHttpClient 下载文件使用的 http client，优先使用 WithHTTPClient 的配置
+func (c *larkClient) downloadHttpClient() larkcore.HttpClient {
+	if c.httpClient != nil {
+		return c.httpClient
+	}
+	return defaultDownloadClient
+}
+
+// tokenInvalidCodes tenant_access_token 无效或过期的错误码，如重置了应用密钥
+var tokenInvalidCodes = map[int]bool{
+	99991661: true,
+	99991663: true,
+	99991668: true,
+}
+
+// clearTenantToken token 提前失效时清除缓存，下次请求重新获取
+func (c *larkClient) clearTenantToken() {
+	c.tenantToken.mu.Lock()
+	defer c.tenantToken.mu.Unlock()
+	c.tenantToken.token = ""
+}
+
+// getStream 以 tenant_access_token GET 请求开放平台接口，不读取响应体，用于下载文件。
+// 接口返回错误时读取错误信息并返回 *LarkError，成功时调用方负责关闭 resp.Body。
+// token 失效时清除缓存并重新获取 token 重试一次
+func (c *larkClient) getStream(ctx context.Context, name, apiPath string, args ...interface{}) (*http.Response, error) {
+	resp, err := c.doGetStream(ctx, name, apiPath, args...)
+	var larkErr *LarkError
+	if errors.As(err, &larkErr) && tokenInvalidCodes[larkErr.Code] {
+		c.clearTenantToken()
+		resp, err = c.doGetStream(ctx, name, apiPath, args...)
+	}
+	if err != nil {
+		c.Alert(err)
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *larkClient) doGetStream(ctx context.Context, name, apiPath string, args ...interface{}) (*http.Response, error) {
+	token, err := c.tenantAccessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(c.baseUrl, "/")+apiPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := c.downloadHttpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusOK && !strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		return resp, nil
+	}
+	// 失败时响应体为 json 格式的错误信息
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	apiResp := &larkcore.ApiResp{StatusCode: resp.StatusCode, Header: resp.Header, RawBody: body}
+	codeErr := larkcore.CodeError{}
+	if json.Unmarshal(body, &codeErr) != nil || codeErr.Code == 0 {
+		codeErr.Msg = http.StatusText(resp.StatusCode)
+	}
+	return nil, newLarkError(name, apiResp, codeErr, args...)
+}
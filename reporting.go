@@ -0,0 +1,209 @@
+package lark_sdk
+
+import (
+	"context"
+
+	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
+	larkcontact "github.com/larksuite/oapi-sdk-go/v3/service/contact/v3"
+)
+
+// GetManagerChain 从直属上级开始逐级向上的汇报链，不包含自身；maxDepth 为 0 表示不限制层数。
+// 用户没有直属上级时，取所在部门（由近到远）中第一个不是自己的部门负责人作为上级。
+// 汇报链成环时在回到已出现的用户之前停止
+func (c *larkClient) GetManagerChain(ctx context.Context, userId string, maxDepth int) ([]*larkcontact.User, error) {
+	res := make([]*larkcontact.User, 0)
+	seen := map[string]bool{userId: true}
+	user, err := c.GetUserByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	for maxDepth <= 0 || len(res) < maxDepth {
+		leaderId, err := c.getManagerId(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		if leaderId == "" || seen[leaderId] {
+			break
+		}
+		seen[leaderId] = true
+		user, err = c.GetUserByUserId(ctx, leaderId)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, user)
+	}
+	return res, nil
+}
+
+// getManagerId 用户的直属上级，没有时回退到部门负责人，都没有时返回空
+func (c *larkClient) getManagerId(ctx context.Context, user *larkcontact.User) (string, error) {
+	if leaderId := larkcore.StringValue(user.LeaderUserId); leaderId != "" {
+		return leaderId, nil
+	}
+	userId := larkcore.StringValue(user.UserId)
+	for _, deptId := range user.DepartmentIds {
+		seen := make(map[string]bool)
+		for deptId != "" && deptId != rootDeptId && !seen[deptId] {
+			seen[deptId] = true
+			dept, err := c.GetDeptById(ctx, DepartmentId, deptId)
+			if err != nil {
+				return "", err
+			}
+			if leaderId := larkcore.StringValue(dept.LeaderUserId); leaderId != "" && leaderId != userId {
+				return leaderId, nil
+			}
+			deptId = larkcore.StringValue(dept.ParentDepartmentId)
+		}
+	}
+	return "", nil
+}
+
+// GetDirectReports 直属上级为 userId 的用户，只看用户的直属上级字段，不含部门负责人回退。
+// 汇报关系索引需要遍历全部用户建立，配置了 WithCache 时会缓存索引，否则每次调用都会重新遍历，
+// 多次查询时使用 BuildReportingLine 或 NewReportingLineFromSnapshot
+func (c *larkClient) GetDirectReports(ctx context.Context, userId string) ([]*larkcontact.User, error) {
+	reports, err := c.reportIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.usersInOrder(ctx, reports[userId])
+}
+
+// GetAllReports userId 的全部下属（直接和间接），按层级由近到远排列，索引的缓存同 GetDirectReports
+func (c *larkClient) GetAllReports(ctx context.Context, userId string) ([]*larkcontact.User, error) {
+	reports, err := c.reportIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.usersInOrder(ctx, allReports(reports, userId))
+}
+
+// reportIndex 上级 user_id 到直属下属 user_id 的索引，优先从缓存读取
+func (c *larkClient) reportIndex(ctx context.Context) (map[string][]string, error) {
+	if reports, ok := cacheGet[map[string][]string](ctx, c, c.cacheKey("reports")); ok {
+		return reports, nil
+	}
+	line, err := c.BuildReportingLine(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return line.reports, nil
+}
+
+// usersInOrder 按 ids 的顺序批量获取用户，获取不到的用户跳过
+func (c *larkClient) usersInOrder(ctx context.Context, ids []string) ([]*larkcontact.User, error) {
+	res := make([]*larkcontact.User, 0, len(ids))
+	if len(ids) == 0 {
+		return res, nil
+	}
+	users, err := c.BatchGetUsers(ctx, ids, UserId)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		if user, ok := users[id]; ok {
+			res = append(res, user)
+		}
+	}
+	return res, nil
+}
+
+// ReportingLine 全员的汇报关系，以 user_id 为 key
+type ReportingLine struct {
+	users   map[string]*larkcontact.User
+	reports map[string][]string
+}
+
+// BuildReportingLine 拉取全部用户并按直属上级建立汇报关系
+func (c *larkClient) BuildReportingLine(ctx context.Context) (*ReportingLine, error) {
+	users := make([]*larkcontact.User, 0)
+	err := c.StreamAllUser(ctx, func(user *larkcontact.User) error {
+		users = append(users, user)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	line := NewReportingLine(users)
+	cacheSet(ctx, c, c.cacheKey("reports"), line.reports)
+	return line, nil
+}
+
+// NewReportingLineFromSnapshot 由组织架构快照建立汇报关系，用户只包含快照中的字段
+func NewReportingLineFromSnapshot(snapshot *OrgSnapshot) *ReportingLine {
+	users := make([]*larkcontact.User, 0, len(snapshot.Users))
+	for _, u := range snapshot.Users {
+		users = append(users, &larkcontact.User{
+			UserId:        larkcore.StringPtr(u.UserId),
+			OpenId:        larkcore.StringPtr(u.OpenId),
+			Name:          larkcore.StringPtr(u.Name),
+			DepartmentIds: u.DepartmentIds,
+			LeaderUserId:  larkcore.StringPtr(u.LeaderUserId),
+		})
+	}
+	return NewReportingLine(users)
+}
+
+// NewReportingLine 由 user_id 类型的用户列表建立汇报关系
+func NewReportingLine(users []*larkcontact.User) *ReportingLine {
+	l := &ReportingLine{
+		users:   make(map[string]*larkcontact.User),
+		reports: make(map[string][]string),
+	}
+	for _, user := range users {
+		if user == nil || user.UserId == nil {
+			continue
+		}
+		l.users[*user.UserId] = user
+		if leaderId := larkcore.StringValue(user.LeaderUserId); leaderId != "" && leaderId != *user.UserId {
+			l.reports[leaderId] = append(l.reports[leaderId], *user.UserId)
+		}
+	}
+	return l
+}
+
+// Leader 直属上级，没有或不在列表中时返回 nil
+func (l *ReportingLine) Leader(userId string) *larkcontact.User {
+	user, ok := l.users[userId]
+	if !ok {
+		return nil
+	}
+	return l.users[larkcore.StringValue(user.LeaderUserId)]
+}
+
+// DirectReports 直属下属
+func (l *ReportingLine) DirectReports(userId string) []*larkcontact.User {
+	res := make([]*larkcontact.User, 0)
+	for _, id := range l.reports[userId] {
+		res = append(res, l.users[id])
+	}
+	return res
+}
+
+// AllReports 全部下属，广度优先，汇报关系成环时每个用户只出现一次且不包含 userId 自身
+func (l *ReportingLine) AllReports(userId string) []*larkcontact.User {
+	res := make([]*larkcontact.User, 0)
+	for _, id := range allReports(l.reports, userId) {
+		res = append(res, l.users[id])
+	}
+	return res
+}
+
+func allReports(reports map[string][]string, userId string) []string {
+	res := make([]string, 0)
+	seen := map[string]bool{userId: true}
+	queue := []string{userId}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, reportId := range reports[id] {
+			if seen[reportId] {
+				continue
+			}
+			seen[reportId] = true
+			res = append(res, reportId)
+			queue = append(queue, reportId)
+		}
+	}
+	return res
+}
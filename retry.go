@@ -0,0 +1,219 @@
+package lark_sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
+	"github.com/pkg/errors"
+)
+
+// RetryPolicy 失败重试与限流策略，作用于 client 发出的每一个 http 请求
+type RetryPolicy struct {
+	MaxRetry    int           // 最大重试次数，0 表示不重试
+	Interval    time.Duration // 第一次重试前的等待时间，之后指数增长
+	MaxInterval time.Duration // 单次等待时间上限
+	QPS         float64       // 每个接口每秒请求数上限，0 表示不限流
+	Burst       int           // 令牌桶容量，QPS > 0 时生效
+}
+
+var defaultRetryPolicy = RetryPolicy{
+	MaxRetry:    3,
+	Interval:    time.Second,
+	MaxInterval: 30 * time.Second,
+}
+
+const rateLimitResetHeader = "x-ogw-ratelimit-reset"
+
+type retryHttpClient struct {
+	next    larkcore.HttpClient
+	policy  RetryPolicy
+	limiter *endpointLimiter
+}
+
+// newRetryHttpClient 包装 next，对限流、5xx 及网络错误按 policy 重试，同时按接口限流
+func newRetryHttpClient(next larkcore.HttpClient, policy RetryPolicy) *retryHttpClient {
+	if next == nil {
+		next = http.DefaultClient
+	}
+	return &retryHttpClient{
+		next:    next,
+		policy:  policy,
+		limiter: newEndpointLimiter(policy.QPS, policy.Burst),
+	}
+}
+
+func (h *retryHttpClient) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	key := endpointKey(req)
+	for attempt := 0; ; attempt++ {
+		if err := h.limiter.wait(ctx, key); err != nil {
+			return nil, err
+		}
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+		resp, err := h.next.Do(req)
+		retry, wait := h.shouldRetry(req, resp, err)
+		if !retry || attempt >= h.policy.MaxRetry || (req.Body != nil && req.GetBody == nil) {
+			return resp, err
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+		if wait <= 0 {
+			wait = h.backoff(attempt)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// shouldRetry 判断是否需要重试，若服务端给出了限流重置时间一并返回。
+// 网络错误和 5xx 时服务端可能已经处理了请求，只重试幂等的请求；
+// 限流时服务端保证请求未被处理，所有请求都可以重试
+func (h *retryHttpClient) shouldRetry(req *http.Request, resp *http.Response, err error) (bool, time.Duration) {
+	if err != nil {
+		return isIdempotent(req) && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded), 0
+	}
+	wait := rateLimitReset(resp.Header)
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true, wait
+	}
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return isIdempotent(req), wait
+	}
+	if !strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		return false, 0
+	}
+	body, readErr := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if readErr != nil {
+		return false, 0
+	}
+	var result struct {
+		Code    int `json:"code"`
+		ErrCode int `json:"err_code"`
+	}
+	if json.Unmarshal(body, &result) != nil {
+		return false, 0
+	}
+	return rateLimitedCodes[result.Code] || rateLimitedCodes[result.ErrCode], wait
+}
+
+// isIdempotent POST、PATCH 可能创建实例、记录等，重复发送会产生重复数据
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// backoff 指数退避，带抖动
+func (h *retryHttpClient) backoff(attempt int) time.Duration {
+	d := h.policy.Interval << attempt
+	if d <= 0 || (h.policy.MaxInterval > 0 && d > h.policy.MaxInterval) {
+		d = h.policy.MaxInterval
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+func rateLimitReset(header http.Header) time.Duration {
+	reset, err := strconv.Atoi(header.Get(rateLimitResetHeader))
+	if err != nil || reset <= 0 {
+		return 0
+	}
+	return time.Duration(reset)*time.Second + time.Duration(rand.Int63n(int64(100*time.Millisecond)))
+}
+
+// endpointKey 取 path 前四段作为接口标识，如 /open-apis/contact/v3/users，
+// 使路径参数不同的同一接口共用一个令牌桶
+func endpointKey(req *http.Request) string {
+	segments := strings.SplitN(strings.TrimPrefix(req.URL.Path, "/"), "/", 5)
+	if len(segments) > 4 {
+		segments = segments[:4]
+	}
+	return req.Method + " /" + strings.Join(segments, "/")
+}
+
+type endpointLimiter struct {
+	qps     float64
+	burst   float64
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newEndpointLimiter(qps float64, burst int) *endpointLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &endpointLimiter{
+		qps:     qps,
+		burst:   float64(burst),
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// wait 阻塞直到 key 对应的令牌桶中有可用令牌
+func (l *endpointLimiter) wait(ctx context.Context, key string) error {
+	if l.qps <= 0 {
+		return nil
+	}
+	l.mu.Lock()
+	now := time.Now()
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens += now.Sub(b.last).Seconds() * l.qps
+	if b.tokens > l.burst {
+		b.tokens = l.burst
+	}
+	b.last = now
+	b.tokens--
+	var delay time.Duration
+	if b.tokens < 0 {
+		delay = time.Duration(-b.tokens / l.qps * float64(time.Second))
+	}
+	l.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -5,15 +5,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"os"
 	"strconv"
 	"time"
 
 	"github.com/YueY4n9/gotools/echo"
-	_map "github.com/YueY4n9/gotools/map"
 	_slice "github.com/YueY4n9/gotools/slice"
 	"github.com/google/uuid"
 	lark "github.com/larksuite/oapi-sdk-go/v3"
+	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
+	"github.com/larksuite/oapi-sdk-go/v3/event/dispatcher"
 	larkapplication "github.com/larksuite/oapi-sdk-go/v3/service/application/v6"
 	larkapproval "github.com/larksuite/oapi-sdk-go/v3/service/approval/v4"
 	larkattendance "github.com/larksuite/oapi-sdk-go/v3/service/attendance/v1"
@@ -21,6 +21,7 @@ import (
 	larkbitable "github.com/larksuite/oapi-sdk-go/v3/service/bitable/v1"
 	larkcalendar "github.com/larksuite/oapi-sdk-go/v3/service/calendar/v4"
 	larkcontact "github.com/larksuite/oapi-sdk-go/v3/service/contact/v3"
+	larkcorehrv1 "github.com/larksuite/oapi-sdk-go/v3/service/corehr/v1"
 	larkcorehr "github.com/larksuite/oapi-sdk-go/v3/service/corehr/v2"
 	larkdrive "github.com/larksuite/oapi-sdk-go/v3/service/drive/v1"
 	larkehr "github.com/larksuite/oapi-sdk-go/v3/service/ehr/v1"
@@ -31,11 +32,6 @@ import (
 	"github.com/pkg/errors"
 )
 
-const (
-	maxRetry  int = 3
-	sleepTime     = time.Second
-)
-
 type LarkClient interface {
 	Client() *lark.Client
 	GetAppName() string
@@ -45,20 +41,62 @@ type LarkClient interface {
 	GetUserById(ctx context.Context, id, userIdType, deptIdType string) (*larkcontact.User, error)
 	GetUserByUserId(ctx context.Context, userId string) (*larkcontact.User, error)
 	GetUserByOpenId(ctx context.Context, openId string) (*larkcontact.User, error)
+	BatchGetUsers(ctx context.Context, ids []string, idType string) (map[string]*larkcontact.User, error)
+	// Deprecated: 人事（标准版）接口，使用 GetEmployee
 	GetEmpByUserId(ctx context.Context, userId string) (*larkehr.Employee, error)
 	GetEmpNameMap(ctx context.Context, userIds []string) (map[string]string, error)
+	// Deprecated: 人事（标准版）接口，使用 ListEmployees
 	ListEmp(ctx context.Context, userIds []string) ([]*larkehr.Employee, error)
 	AllUser(ctx context.Context) ([]*larkcontact.User, error)
+	// Deprecated: 人事（标准版）接口，使用 AllEmployees
 	AllEmp(ctx context.Context) ([]*larkehr.Employee, error)
+	AllEmpPager() *Pager[*larkehr.Employee]
 	ListUserByDeptId(ctx context.Context, deptIdType, deptId string) ([]*larkcontact.User, error)
+	ListDeptUserPager(deptIdType, deptId string) *Pager[*larkcontact.User]
 	AllUserId(ctx context.Context) ([]string, error)
+	StreamAllUser(ctx context.Context, fn func(*larkcontact.User) error, opts ...StreamOption) error
+	StreamAllEmp(ctx context.Context, fn func(*larkehr.Employee) error, opts ...StreamOption) error
+	StreamAllUserId(ctx context.Context, fn func(string) error, opts ...StreamOption) error
+	StreamUserByDeptId(ctx context.Context, deptIdType, deptId string, fn func(*larkcontact.User) error, opts ...StreamOption) error
 	ListUserIdByDeptId(ctx context.Context, deptIdType, deptId string) ([]string, error)
+	ConvertUserIds(ctx context.Context, ids []string, fromType, toType string) (map[string]string, error)
 
 	//部门
 	GetDeptById(ctx context.Context, deptIdType, deptId string) (*larkcontact.Department, error)
 	ListChildDeptByDeptId(ctx context.Context, deptIdType string, deptId string) ([]*larkcontact.Department, error)
+	ListChildDeptPager(deptIdType, deptId string) *Pager[*larkcontact.Department]
 	ListChildDeptIdByDeptId(ctx context.Context, deptIdType string, deptId string) ([]string, error)
 	ListParentDeptByDeptId(ctx context.Context, deptIdType string, deptId string) ([]*larkcontact.Department, error)
+	ListParentDeptPager(deptIdType, deptId string) *Pager[*larkcontact.Department]
+	BuildDeptTree(ctx context.Context) (*DeptTree, error)
+
+	// 员工
+	GetEmployee(ctx context.Context, userId string) (*Employee, error)
+	ListEmployees(ctx context.Context, userIds []string) ([]*Employee, error)
+	AllEmployees(ctx context.Context) ([]*Employee, error)
+	QueryEmployees(ctx context.Context, q *EmployeeQuery) ([]*Employee, error)
+	ListNewHires(ctx context.Context, since time.Time) ([]*Employee, error)
+	ListLeavers(ctx context.Context, since time.Time) ([]*Employee, error)
+	BatchGetCoreHREmp(ctx context.Context, userIds []string) ([]*larkcorehr.Employee, error)
+	SearchCoreHREmpPager(body *larkcorehr.SearchEmployeeReqBody) *Pager[*larkcorehr.Employee]
+	ListJobData(ctx context.Context, userIds []string) ([]*larkcorehr.EmployeeJobData, error)
+	ListContract(ctx context.Context, userIds []string) ([]*larkcorehr.Contract, error)
+	SearchPreHirePager(body *larkcorehr.SearchPreHireReqBody) *Pager[*larkcorehr.PreHire]
+	SearchOffboardingPager(body *larkcorehrv1.SearchOffboardingReqBody) *Pager[*larkcorehrv1.Offboarding]
+
+	// 组织架构
+	CaptureOrgSnapshot(ctx context.Context) (*OrgSnapshot, error)
+
+	// 汇报关系
+	GetManagerChain(ctx context.Context, userId string, maxDepth int) ([]*larkcontact.User, error)
+	GetDirectReports(ctx context.Context, userId string) ([]*larkcontact.User, error)
+	GetAllReports(ctx context.Context, userId string) ([]*larkcontact.User, error)
+	BuildReportingLine(ctx context.Context) (*ReportingLine, error)
+
+	// 缓存
+	InvalidateUser(ctx context.Context, ids ...string)
+	InvalidateDept(ctx context.Context, ids ...string)
+	RegisterContactEvents(d *dispatcher.EventDispatcher) *dispatcher.EventDispatcher
 
 	// 消息
 	SendMsg(ctx context.Context, receiveIdType, receivedId, msgType, content string) error
@@ -67,17 +105,26 @@ type LarkClient interface {
 	// 审批
 	SubscribeApproval(ctx context.Context, code string) error
 	UnsubscribeApproval(ctx context.Context, code string) error
+	StartApprovalEventClient(ctx context.Context, h *ApprovalEventHandler) error
 	GetApprovalDefineByCode(ctx context.Context, code string) (*larkapproval.GetApprovalRespData, error)
+	GetApprovalDefinition(ctx context.Context, code string) (*ApprovalDefinition, error)
 	ListApprovalInstIdByCode(ctx context.Context, code, startTime, endTime string) ([]string, error)
+	ListApprovalInstIdPager(code, startTime, endTime string) *Pager[string]
 	GetApprovalInstById(ctx context.Context, instId string) (*larkapproval.GetInstanceRespData, error)
+	GetApprovalInstance(ctx context.Context, instCode string) (*Instance, error)
 	SearchApprovalInst(ctx context.Context, userId, approvalCode, instCode, instStatus string) ([]*larkapproval.InstanceSearchItem, error)
-	CreateApprovalInst(ctx context.Context, approvalCode, userId string, form interface{}, nodeApprover []*larkapproval.NodeApprover) error
+	SearchApprovalInstPager(userId, approvalCode, instCode, instStatus string) *Pager[*larkapproval.InstanceSearchItem]
+	DownloadFormFiles(ctx context.Context, widgets []FormWidget, sink FileSink) error
+	UploadApprovalFile(ctx context.Context, name, fileType string, r io.Reader) (string, error)
+	NewFormBuilderByCode(ctx context.Context, approvalCode string) (*FormBuilder, error)
+	CreateApprovalInst(ctx context.Context, approvalCode, userId string, form interface{}, nodeApprover []*larkapproval.NodeApprover, opts ...CreateInstOption) (string, error)
 	RollbackApprovalTask(ctx context.Context, currUserId, currTaskId, reason string, defKeys []string) error
 	AddSign(ctx context.Context, operatorId, approvalCode, instCode, taskId, comment string, addSignUserIds []string, addSignType, approvalMethod int) error
 	ApproveTask(ctx context.Context, approvalCode, instCode, userId, comment, taskId, form string) error
 	CcApprovalInst(ctx context.Context, approvalCode, instCode, fromUserId, comment string, ccUserIds []string) error
 	AddInstComment(ctx context.Context, instCode, userId, comment string) error
 	SearchUserApprovalTask(ctx context.Context, userId, taskStatus string) ([]*larkapproval.TaskSearchItem, error)
+	SearchUserApprovalTaskPager(userId, taskStatus string) *Pager[*larkapproval.TaskSearchItem]
 	RejectTask(ctx context.Context, approvalCode, instCode, userId, comment, taskId string) error
 
 	// 假勤
@@ -88,15 +135,19 @@ type LarkClient interface {
 
 	// 会议室
 	ListRoom(ctx context.Context, roomLevelId string) ([]*larkvc.Room, error)
+	ListRoomPager(roomLevelId string) *Pager[*larkvc.Room]
 	CheckRoomFree(ctx context.Context, roomId, timeMin, timeMax string) (bool, error)
 	SetCalendarRoom(ctx context.Context, calendarId, eventId, roomId string) error
 	SetCalendarUsers(ctx context.Context, calendarId, eventId string, userIds []string) error
 	ListCalendarEvent(ctx context.Context, calendarId string) ([]*larkcalendar.CalendarEvent, error)
+	ListCalendarEventPager(calendarId string) *Pager[*larkcalendar.CalendarEvent]
 	CreateCalendarEvent(ctx context.Context, calendarId, summary, startTs, endTs string) (*larkcalendar.CalendarEvent, error)
 
 	// 云文档
 	GetSpaceNode(ctx context.Context, objType, token string) (*larkwiki.Node, error)
 	ListBitableRecord(ctx context.Context, appToken, tableId, userIdType string, fieldNames []string, sort []*larkbitable.Sort, filter *larkbitable.FilterInfo) ([]*larkbitable.AppTableRecord, error)
+	ListBitableRecordPager(appToken, tableId, userIdType string, fieldNames []string, sort []*larkbitable.Sort, filter *larkbitable.FilterInfo) *Pager[*larkbitable.AppTableRecord]
+	SearchAppTableRecordPager(appToken, tableId string, fieldNames []string, info *larkbitable.FilterInfo) *Pager[*larkbitable.AppTableRecord]
 	InsertBitableRecord(ctx context.Context, appToken, tableId, userIdType string, records []*larkbitable.AppTableRecord) error
 	InsertBitable1Record(ctx context.Context, appToken, tableId, userIdType string, record *larkbitable.AppTableRecord) error
 	UpdateBitableRecord(ctx context.Context, appToken, tableId, userIdType string, records []*larkbitable.AppTableRecord) error
@@ -108,39 +159,65 @@ type LarkClient interface {
 	GetProcess(ctx context.Context, processId string) (*larkcorehr.GetProcessRespData, error)
 
 	// 其他
-	GetAttachment(ctx context.Context, token string) error
+	GetAttachment(ctx context.Context, token string) (io.ReadCloser, *AttachmentMeta, error)
+	DownloadAttachmentTo(ctx context.Context, token, dir string) (string, error)
 	ListAttendanceRecord(ctx context.Context, userIds []string, dateFrom, dateTo int) ([]*larkattendance.UserTask, error)
 	ListRoleMember(ctx context.Context, roleId string) ([]*larkcontact.FunctionalRoleMember, error)
 	GetAppInfo(appId string) *larkapplication.Application
 	AddAttendanceFlow(ctx context.Context, userId, locationName, checkTime string) error
 	GetLog(ctx context.Context, appId, apiKey string) ([]*larksecurityandcompliance.OpenapiLog, error)
+	GetLogPager(appId, apiKey string) *Pager[*larksecurityandcompliance.OpenapiLog]
 	Alert(err error)
 }
 
 type larkClient struct {
-	appId       string
-	appSecret   string
-	appName     string
-	debugId     string
-	debugSecret string
-	adminUserId string
-	client      *lark.Client
+	appId           string
+	appSecret       string
+	appName         string
+	debugId         string
+	debugSecret     string
+	adminUserId     string
+	debugClient     LarkClient
+	alerter         Alerter
+	retryPolicy     RetryPolicy
+	concurrency     int
+	useCoreHR       bool
+	maxDownloadSize int64
+	cache           Cache
+	cacheTTL        time.Duration
+	httpClient      larkcore.HttpClient
+	baseUrl         string
+	tenantToken     tenantTokenCache
+	logger          larkcore.Logger
+	larkOpts        []lark.ClientOptionFunc
+	client          *lark.Client
 }
 
-func NewClient(appId, appSecret string, debug ...string) LarkClient {
+func NewClient(appId, appSecret string, opts ...Option) LarkClient {
 	c := &larkClient{
-		appId:     appId,
-		appSecret: appSecret,
-		client:    lark.NewClient(appId, appSecret, lark.WithEnableTokenCache(true)),
-	}
-	if len(debug) >= 2 {
-		c.debugId = debug[0]
-		c.debugSecret = debug[1]
-		c.appName = *NewClient(c.debugId, c.debugSecret).GetAppInfo(appId).AppName
-		c.adminUserId = "3291738c"
+		appId:           appId,
+		appSecret:       appSecret,
+		retryPolicy:     defaultRetryPolicy,
+		maxDownloadSize: defaultMaxDownloadSize,
+		baseUrl:         lark.FeishuBaseUrl,
+		larkOpts:        []lark.ClientOptionFunc{lark.WithEnableTokenCache(true)},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.larkOpts = append(c.larkOpts, lark.WithHttpClient(newRetryHttpClient(c.httpClient, c.retryPolicy)))
+	c.client = lark.NewClient(appId, appSecret, c.larkOpts...)
+	if c.debugId != "" {
+		c.debugClient = NewClient(c.debugId, c.debugSecret, WithAlerter(NewNopAlerter()))
+		if appInfo := c.debugClient.GetAppInfo(appId); appInfo != nil && appInfo.AppName != nil {
+			c.appName = *appInfo.AppName
+		}
+		if c.alerter == nil && c.adminUserId != "" {
+			c.alerter = NewThrottleAlerter(NewCardAlerter(c.debugClient, appId, c.appName, c.adminUserId), defaultAlertInterval)
+		}
 	}
-	if len(debug) >= 3 {
-		c.adminUserId = debug[2]
+	if s, ok := c.alerter.(loggerSetter); ok && c.logger != nil {
+		s.setLogger(c.logger)
 	}
 	return c
 }
@@ -159,6 +236,10 @@ func (c *larkClient) GetUserByOpenId(ctx context.Context, openId string) (*larkc
 	return c.GetUserById(ctx, openId, OpenId, DepartmentId)
 }
 func (c *larkClient) GetUserById(ctx context.Context, id, userIdType, deptIdType string) (*larkcontact.User, error) {
+	key := c.cacheKey("user", userIdType, deptIdType, id)
+	if user, ok := cacheGet[*larkcontact.User](ctx, c, key); ok {
+		return user, nil
+	}
 	resp, err := c.client.Contact.User.Get(ctx, larkcontact.NewGetUserReqBuilder().
 		UserId(id).
 		UserIdType(userIdType).
@@ -169,9 +250,16 @@ func (c *larkClient) GetUserById(ctx context.Context, id, userIdType, deptIdType
 		return nil, err
 	}
 	if !resp.Success() {
-		c.Alert(errors.New(string(resp.RawBody)))
-		return nil, resp
+		err = newLarkError("GetUserById", resp.ApiResp, resp.CodeError, "id", id, "user_id_type", userIdType)
+		c.Alert(err)
+		return nil, err
 	}
+	if resp.Data == nil {
+		err = newEmptyDataError("GetUserById", resp.ApiResp, "id", id, "user_id_type", userIdType)
+		c.Alert(err)
+		return nil, err
+	}
+	cacheSet(ctx, c, key, resp.Data.User)
 	return resp.Data.User, nil
 }
 func (c *larkClient) getEmp(ctx context.Context, userIdType string, userIds []string) ([]*larkehr.Employee, error) {
@@ -188,13 +276,14 @@ func (c *larkClient) getEmp(ctx context.Context, userIdType string, userIds []st
 			return nil, err
 		}
 		if !resp.Success() {
-			if resp.Code == 1241001 {
-				time.Sleep(sleepTime)
-				return c.getEmp(ctx, userIdType, userIds)
-			} else {
-				c.Alert(errors.New(string(resp.RawBody)))
-				return nil, resp
-			}
+			err = newLarkError("getEmp", resp.ApiResp, resp.CodeError, "user_ids", chunk)
+			c.Alert(err)
+			return nil, err
+		}
+		if resp.Data == nil {
+			err = newEmptyDataError("getEmp", resp.ApiResp, "user_ids", chunk)
+			c.Alert(err)
+			return nil, err
 		}
 		res = append(res, resp.Data.Items...)
 	}
@@ -212,15 +301,26 @@ func (c *larkClient) GetEmpByUserId(ctx context.Context, userId string) (*larkeh
 	return employees[0], nil
 }
 func (c *larkClient) GetEmpNameMap(ctx context.Context, userIds []string) (map[string]string, error) {
-	employees, err := c.getEmp(ctx, UserId, userIds)
+	res := make(map[string]string)
+	missIds := make([]string, 0, len(userIds))
+	for _, userId := range userIds {
+		if name, ok := cacheGet[string](ctx, c, c.cacheKey("emp_name", userId)); ok {
+			res[userId] = name
+		} else {
+			missIds = append(missIds, userId)
+		}
+	}
+	if len(missIds) == 0 {
+		return res, nil
+	}
+	employees, err := c.listEmployees(ctx, missIds, false)
 	if err != nil {
-		c.Alert(err)
 		return nil, err
 	}
-	res := make(map[string]string)
 	for _, emp := range employees {
-		if emp.UserId != nil && emp.SystemFields.Name != nil {
-			res[*emp.UserId] = *emp.SystemFields.Name
+		if emp.UserId != "" && emp.Name != "" {
+			res[emp.UserId] = emp.Name
+			cacheSet(ctx, c, c.cacheKey("emp_name", emp.UserId), emp.Name)
 		}
 	}
 	return res, nil
@@ -229,13 +329,25 @@ func (c *larkClient) ListEmp(ctx context.Context, userIds []string) ([]*larkehr.
 	return c.getEmp(ctx, UserId, userIds)
 }
 func (c *larkClient) AllEmp(ctx context.Context) ([]*larkehr.Employee, error) {
-	res := make([]*larkehr.Employee, 0)
-	for hasMore, pageToken := true, ""; hasMore; {
+	return c.AllEmpPager().Collect(ctx)
+}
+func (c *larkClient) AllEmpPager() *Pager[*larkehr.Employee] {
+	return c.listEmpPager([]int{2, 4}, nil)
+}
+
+// listEmpPager 按员工状态和雇员类型列出人事（标准版）的员工，为空表示不限制
+func (c *larkClient) listEmpPager(status, empTypes []int) *Pager[*larkehr.Employee] {
+	return NewPager(func(ctx context.Context, pageToken string) (*Page[*larkehr.Employee], error) {
 		employeeReqBuilder := larkehr.NewListEmployeeReqBuilder().
 			View("full").
 			PageSize(100).
-			UserIdType(UserId).
-			Status([]int{2, 4})
+			UserIdType(UserId)
+		if len(status) > 0 {
+			employeeReqBuilder.Status(status)
+		}
+		if len(empTypes) > 0 {
+			employeeReqBuilder.Type(empTypes)
+		}
 		if pageToken != "" {
 			employeeReqBuilder.PageToken(pageToken)
 		}
@@ -246,85 +358,84 @@ func (c *larkClient) AllEmp(ctx context.Context) ([]*larkehr.Employee, error) {
 			return nil, err
 		}
 		if !resp.Success() {
-			c.Alert(errors.New(string(resp.RawBody)))
-			return nil, resp
-		}
-		hasMore = *resp.Data.HasMore
-		if hasMore {
-			pageToken = *resp.Data.PageToken
-		}
-		for _, item := range resp.Data.Items {
-			res = append(res, item)
+			err = newLarkError("AllEmp", resp.ApiResp, resp.CodeError, "page_token", pageToken)
+			c.Alert(err)
+			return nil, err
 		}
-	}
-	return res, nil
-}
-func (c *larkClient) AllUser(ctx context.Context) (res []*larkcontact.User, err error) {
-	startTime := time.Now()
-	for i := 0; i < maxRetry; i++ {
-		res, err = c.ListUserByDeptId(ctx, DepartmentId, "0")
-		if err == nil {
-			break
-		} else {
+		if resp.Data == nil {
+			err = newEmptyDataError("AllEmp", resp.ApiResp, "page_token", pageToken)
 			c.Alert(err)
-			time.Sleep(sleepTime)
+			return nil, err
 		}
+		return &Page[*larkehr.Employee]{
+			Items:     resp.Data.Items,
+			PageToken: larkcore.StringValue(resp.Data.PageToken),
+			HasMore:   larkcore.BoolValue(resp.Data.HasMore),
+		}, nil
+	})
+}
+func (c *larkClient) AllUser(ctx context.Context) ([]*larkcontact.User, error) {
+	res := make([]*larkcontact.User, 0)
+	err := c.StreamAllUser(ctx, func(user *larkcontact.User) error {
+		res = append(res, user)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	endTime := time.Now()
-	echo.Json(endTime.Sub(startTime).String())
-	return res, err
+	return res, nil
 }
 func (c *larkClient) ListUserByDeptId(ctx context.Context, deptIdType, deptId string) ([]*larkcontact.User, error) {
 	res := make([]*larkcontact.User, 0)
-	childDeptIds, err := c.ListChildDeptIdByDeptId(ctx, deptIdType, deptId)
+	err := c.StreamUserByDeptId(ctx, deptIdType, deptId, func(user *larkcontact.User) error {
+		res = append(res, user)
+		return nil
+	})
 	if err != nil {
-		c.Alert(err)
 		return nil, err
 	}
-	for _, childDeptId := range childDeptIds {
-		for hasMore, pageToken := true, ""; hasMore; {
-			req := larkcontact.NewFindByDepartmentUserReqBuilder().
-				UserIdType(UserId).
-				DepartmentIdType(DepartmentId).
-				DepartmentId(childDeptId).
-				PageToken(pageToken).
-				PageSize(50).
-				Build()
-			resp, err := c.client.Contact.User.FindByDepartment(ctx, req)
-			if err != nil {
-				c.Alert(err)
-				return nil, err
-			}
-			if !resp.Success() {
-				c.Alert(errors.New(string(resp.RawBody)))
-				return nil, resp
-			}
-			hasMore = *resp.Data.HasMore
-			if hasMore {
-				pageToken = *resp.Data.PageToken
-			}
-			for _, user := range resp.Data.Items {
-				res = append(res, user)
-			}
-		}
-	}
-	userSet := make(map[string]*larkcontact.User)
-	for _, userInfo := range res {
-		userSet[*userInfo.UserId] = userInfo
-	}
-	res = _map.GetValues(userSet)
 	return res, nil
 }
+func (c *larkClient) ListDeptUserPager(deptIdType, deptId string) *Pager[*larkcontact.User] {
+	return NewPager(func(ctx context.Context, pageToken string) (*Page[*larkcontact.User], error) {
+		req := larkcontact.NewFindByDepartmentUserReqBuilder().
+			UserIdType(UserId).
+			DepartmentIdType(deptIdType).
+			DepartmentId(deptId).
+			PageToken(pageToken).
+			PageSize(50).
+			Build()
+		resp, err := c.client.Contact.User.FindByDepartment(ctx, req)
+		if err != nil {
+			c.Alert(err)
+			return nil, err
+		}
+		if !resp.Success() {
+			err = newLarkError("ListDeptUser", resp.ApiResp, resp.CodeError, "department_id", deptId)
+			c.Alert(err)
+			return nil, err
+		}
+		if resp.Data == nil {
+			err = newEmptyDataError("ListDeptUser", resp.ApiResp, "department_id", deptId)
+			c.Alert(err)
+			return nil, err
+		}
+		return &Page[*larkcontact.User]{
+			Items:     resp.Data.Items,
+			PageToken: larkcore.StringValue(resp.Data.PageToken),
+			HasMore:   larkcore.BoolValue(resp.Data.HasMore),
+		}, nil
+	})
+}
 func (c *larkClient) AllUserId(ctx context.Context) ([]string, error) {
 	userIds := make([]string, 0)
-	allEmp, err := c.AllEmp(ctx)
+	err := c.StreamAllUserId(ctx, func(userId string) error {
+		userIds = append(userIds, userId)
+		return nil
+	})
 	if err != nil {
-		c.Alert(err)
 		return nil, err
 	}
-	for _, emp := range allEmp {
-		userIds = append(userIds, *emp.UserId)
-	}
 	return userIds, nil
 }
 func (c *larkClient) ListUserIdByDeptId(ctx context.Context, deptIdType, deptId string) ([]string, error) {
@@ -335,11 +446,17 @@ func (c *larkClient) ListUserIdByDeptId(ctx context.Context, deptIdType, deptId
 		return nil, err
 	}
 	for _, user := range users {
-		res = append(res, *user.UserId)
+		if user.UserId != nil {
+			res = append(res, *user.UserId)
+		}
 	}
 	return _slice.RemoveDuplication(res), nil
 }
 func (c *larkClient) GetDeptById(ctx context.Context, deptIdType, deptId string) (*larkcontact.Department, error) {
+	key := c.cacheKey("dept", deptIdType, deptId)
+	if dept, ok := cacheGet[*larkcontact.Department](ctx, c, key); ok {
+		return dept, nil
+	}
 	req := larkcontact.NewGetDepartmentReqBuilder().
 		DepartmentId(deptId).
 		UserIdType(UserId).
@@ -351,9 +468,16 @@ func (c *larkClient) GetDeptById(ctx context.Context, deptIdType, deptId string)
 		return nil, err
 	}
 	if !resp.Success() {
-		c.Alert(errors.New(string(resp.RawBody)))
-		return nil, resp
+		err = newLarkError("GetDeptById", resp.ApiResp, resp.CodeError, "department_id", deptId)
+		c.Alert(err)
+		return nil, err
+	}
+	if resp.Data == nil {
+		err = newEmptyDataError("GetDeptById", resp.ApiResp, "department_id", deptId)
+		c.Alert(err)
+		return nil, err
 	}
+	cacheSet(ctx, c, key, resp.Data.Department)
 	return resp.Data.Department, nil
 }
 func (c *larkClient) ListChildDeptByDeptId(ctx context.Context, deptIdType string, deptId string) ([]*larkcontact.Department, error) {
@@ -364,7 +488,16 @@ func (c *larkClient) ListChildDeptByDeptId(ctx context.Context, deptIdType strin
 		return nil, err
 	}
 	res = append(res, deptInfo)
-	for hasMore, pageToken := true, ""; hasMore; {
+	children, err := c.ListChildDeptPager(deptIdType, deptId).Collect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return append(res, children...), nil
+}
+
+// ListChildDeptPager 递归列出 deptId 的所有子部门，不包含 deptId 本身
+func (c *larkClient) ListChildDeptPager(deptIdType, deptId string) *Pager[*larkcontact.Department] {
+	return NewPager(func(ctx context.Context, pageToken string) (*Page[*larkcontact.Department], error) {
 		req := larkcontact.NewChildrenDepartmentReqBuilder().
 			DepartmentId(deptId).
 			UserIdType(UserId).
@@ -379,18 +512,21 @@ func (c *larkClient) ListChildDeptByDeptId(ctx context.Context, deptIdType strin
 			return nil, err
 		}
 		if !resp.Success() {
-			c.Alert(errors.New(string(resp.RawBody)))
-			return nil, resp
-		}
-		hasMore = *resp.Data.HasMore
-		if hasMore {
-			pageToken = *resp.Data.PageToken
+			err = newLarkError("ListChildDept", resp.ApiResp, resp.CodeError, "department_id", deptId)
+			c.Alert(err)
+			return nil, err
 		}
-		for _, item := range resp.Data.Items {
-			res = append(res, item)
+		if resp.Data == nil {
+			err = newEmptyDataError("ListChildDept", resp.ApiResp, "department_id", deptId)
+			c.Alert(err)
+			return nil, err
 		}
-	}
-	return res, nil
+		return &Page[*larkcontact.Department]{
+			Items:     resp.Data.Items,
+			PageToken: larkcore.StringValue(resp.Data.PageToken),
+			HasMore:   larkcore.BoolValue(resp.Data.HasMore),
+		}, nil
+	})
 }
 func (c *larkClient) ListChildDeptIdByDeptId(ctx context.Context, deptIdType string, deptId string) ([]string, error) {
 	res := make([]string, 0)
@@ -400,7 +536,7 @@ func (c *larkClient) ListChildDeptIdByDeptId(ctx context.Context, deptIdType str
 		return nil, err
 	}
 	for _, dept := range deptInfoList {
-		if dept.DepartmentId == nil {
+		if dept == nil || dept.DepartmentId == nil {
 			return nil, errors.New("DepartmentId is nil")
 		}
 		res = append(res, *dept.DepartmentId)
@@ -423,9 +559,10 @@ func (c *larkClient) SendMsg(ctx context.Context, receiveIdType, receivedId, msg
 		return err
 	}
 	if !resp.Success() {
-		c.Alert(errors.New(string(resp.RawBody)))
+		err = newLarkError("SendMsg", resp.ApiResp, resp.CodeError, "receive_id", receivedId)
+		c.Alert(err)
 		c.Alert(errors.New(fmt.Sprintf("sendMsg to %s error, content: %s", receivedId, content)))
-		return resp
+		return err
 	}
 	return nil
 }
@@ -462,8 +599,9 @@ func (c *larkClient) SubscribeApproval(ctx context.Context, code string) error {
 		return err
 	}
 	if !resp.Success() {
-		c.Alert(errors.New(string(resp.RawBody)))
-		return resp
+		err = newLarkError("SubscribeApproval", resp.ApiResp, resp.CodeError, "approval_code", code)
+		c.Alert(err)
+		return err
 	}
 	return nil
 }
@@ -477,8 +615,9 @@ func (c *larkClient) UnsubscribeApproval(ctx context.Context, code string) error
 		return err
 	}
 	if !resp.Success() {
-		c.Alert(errors.New(string(resp.RawBody)))
-		return resp
+		err = newLarkError("UnsubscribeApproval", resp.ApiResp, resp.CodeError, "approval_code", code)
+		c.Alert(err)
+		return err
 	}
 	return nil
 }
@@ -495,14 +634,22 @@ func (c *larkClient) GetApprovalDefineByCode(ctx context.Context, code string) (
 		return nil, err
 	}
 	if !resp.Success() {
-		c.Alert(errors.New(string(resp.RawBody)))
-		return nil, resp
+		err = newLarkError("GetApprovalDefineByCode", resp.ApiResp, resp.CodeError, "approval_code", code)
+		c.Alert(err)
+		return nil, err
+	}
+	if resp.Data == nil {
+		err = newEmptyDataError("GetApprovalDefineByCode", resp.ApiResp, "approval_code", code)
+		c.Alert(err)
+		return nil, err
 	}
 	return resp.Data, nil
 }
 func (c *larkClient) ListApprovalInstIdByCode(ctx context.Context, code, startTime, endTime string) ([]string, error) {
-	res := make([]string, 0)
-	for hasMore, pageToken := true, ""; hasMore; {
+	return c.ListApprovalInstIdPager(code, startTime, endTime).Collect(ctx)
+}
+func (c *larkClient) ListApprovalInstIdPager(code, startTime, endTime string) *Pager[string] {
+	return NewPager(func(ctx context.Context, pageToken string) (*Page[string], error) {
 		req := larkapproval.NewListInstanceReqBuilder().
 			ApprovalCode(code).
 			StartTime(startTime).
@@ -516,16 +663,21 @@ func (c *larkClient) ListApprovalInstIdByCode(ctx context.Context, code, startTi
 			return nil, err
 		}
 		if !resp.Success() {
-			c.Alert(errors.New(string(resp.RawBody)))
-			return nil, resp
+			err = newLarkError("ListApprovalInstIdByCode", resp.ApiResp, resp.CodeError, "approval_code", code)
+			c.Alert(err)
+			return nil, err
 		}
-		hasMore = *resp.Data.HasMore
-		if hasMore {
-			pageToken = *resp.Data.PageToken
+		if resp.Data == nil {
+			err = newEmptyDataError("ListApprovalInstIdByCode", resp.ApiResp, "approval_code", code)
+			c.Alert(err)
+			return nil, err
 		}
-		res = append(res, resp.Data.InstanceCodeList...)
-	}
-	return res, nil
+		return &Page[string]{
+			Items:     resp.Data.InstanceCodeList,
+			PageToken: larkcore.StringValue(resp.Data.PageToken),
+			HasMore:   larkcore.BoolValue(resp.Data.HasMore),
+		}, nil
+	})
 }
 func (c *larkClient) GetApprovalInstById(ctx context.Context, instId string) (*larkapproval.GetInstanceRespData, error) {
 	req := larkapproval.NewGetInstanceReqBuilder().
@@ -537,14 +689,22 @@ func (c *larkClient) GetApprovalInstById(ctx context.Context, instId string) (*l
 		return nil, err
 	}
 	if !resp.Success() {
-		c.Alert(errors.New(string(resp.RawBody)))
-		return nil, resp
+		err = newLarkError("GetApprovalInstById", resp.ApiResp, resp.CodeError, "instance_id", instId)
+		c.Alert(err)
+		return nil, err
+	}
+	if resp.Data == nil {
+		err = newEmptyDataError("GetApprovalInstById", resp.ApiResp, "instance_id", instId)
+		c.Alert(err)
+		return nil, err
 	}
 	return resp.Data, nil
 }
 func (c *larkClient) SearchApprovalInst(ctx context.Context, userId, approvalCode, instCode, instStatus string) ([]*larkapproval.InstanceSearchItem, error) {
-	res := make([]*larkapproval.InstanceSearchItem, 0)
-	for hasMore, pageToken := true, ""; hasMore; {
+	return c.SearchApprovalInstPager(userId, approvalCode, instCode, instStatus).Collect(ctx)
+}
+func (c *larkClient) SearchApprovalInstPager(userId, approvalCode, instCode, instStatus string) *Pager[*larkapproval.InstanceSearchItem] {
+	return NewPager(func(ctx context.Context, pageToken string) (*Page[*larkapproval.InstanceSearchItem], error) {
 		req := larkapproval.NewQueryInstanceReqBuilder().
 			PageSize(200).
 			PageToken(pageToken).
@@ -563,65 +723,21 @@ func (c *larkClient) SearchApprovalInst(ctx context.Context, userId, approvalCod
 			return nil, err
 		}
 		if !resp.Success() {
-			c.Alert(errors.New(string(resp.RawBody)))
-			return nil, resp
-		}
-		hasMore = *resp.Data.HasMore
-		if hasMore {
-			pageToken = *resp.Data.PageToken
+			err = newLarkError("SearchApprovalInst", resp.ApiResp, resp.CodeError, "approval_code", approvalCode, "instance_code", instCode)
+			c.Alert(err)
+			return nil, err
 		}
-		for _, item := range resp.Data.InstanceList {
-			res = append(res, item)
+		if resp.Data == nil {
+			err = newEmptyDataError("SearchApprovalInst", resp.ApiResp, "approval_code", approvalCode, "instance_code", instCode)
+			c.Alert(err)
+			return nil, err
 		}
-	}
-	return res, nil
-}
-func (c *larkClient) CreateApprovalInst(ctx context.Context, approvalCode, userId string, form interface{}, nodeApprover []*larkapproval.NodeApprover) error {
-	bytes, err := json.Marshal(form)
-	if err != nil {
-		c.Alert(err)
-		return err
-	}
-	req := larkapproval.NewCreateInstanceReqBuilder().
-		InstanceCreate(larkapproval.NewInstanceCreateBuilder().
-			ApprovalCode(approvalCode).
-			UserId(userId).
-			Form(string(bytes)).
-			NodeApproverUserIdList(nodeApprover).
-			Build()).
-		Build()
-	resp, err := c.client.Approval.Instance.Create(ctx, req)
-	if err != nil {
-		c.Alert(err)
-		return err
-	}
-	if !resp.Success() {
-		c.Alert(errors.New(string(resp.RawBody)))
-		return resp
-	}
-	return nil
-}
-func (c *larkClient) GetAttachment(ctx context.Context, token string) error {
-	resp, err := c.client.Ehr.Attachment.Get(ctx, larkehr.NewGetAttachmentReqBuilder().
-		Token(token).
-		Build())
-	if err != nil {
-		c.Alert(err)
-		return err
-	}
-	if !resp.Success() {
-		c.Alert(errors.New(string(resp.RawBody)))
-		return resp
-	}
-	data, err := io.ReadAll(resp.File)
-	if err != nil {
-		c.Alert(err)
-		return err
-	}
-	if err = os.WriteFile("./temp/"+resp.FileName, data, 0644); err != nil {
-		return err
-	}
-	return nil
+		return &Page[*larkapproval.InstanceSearchItem]{
+			Items:     resp.Data.InstanceList,
+			PageToken: larkcore.StringValue(resp.Data.PageToken),
+			HasMore:   larkcore.BoolValue(resp.Data.HasMore),
+		}, nil
+	})
 }
 
 // ListAttendanceRecord dataFrom,dataTo:20060102
@@ -644,8 +760,14 @@ func (c *larkClient) ListAttendanceRecord(ctx context.Context, userIds []string,
 			return nil, err
 		}
 		if !resp.Success() {
-			c.Alert(errors.New(string(resp.RawBody)))
-			return nil, resp
+			err = newLarkError("ListAttendanceRecord", resp.ApiResp, resp.CodeError, "user_ids", chunk)
+			c.Alert(err)
+			return nil, err
+		}
+		if resp.Data == nil {
+			err = newEmptyDataError("ListAttendanceRecord", resp.ApiResp, "user_ids", chunk)
+			c.Alert(err)
+			return nil, err
 		}
 		for _, userTask := range resp.Data.UserTaskResults {
 			res = append(res, userTask)
@@ -665,8 +787,14 @@ func (c *larkClient) ListRoleMember(ctx context.Context, roleId string) ([]*lark
 		return nil, err
 	}
 	if !resp.Success() {
-		c.Alert(errors.New(string(resp.RawBody)))
-		return nil, resp
+		err = newLarkError("ListRoleMember", resp.ApiResp, resp.CodeError, "role_id", roleId)
+		c.Alert(err)
+		return nil, err
+	}
+	if resp.Data == nil {
+		err = newEmptyDataError("ListRoleMember", resp.ApiResp, "role_id", roleId)
+		c.Alert(err)
+		return nil, err
 	}
 	return resp.Data.Members, nil
 }
@@ -686,8 +814,9 @@ func (c *larkClient) RollbackApprovalTask(ctx context.Context, currUserId, currT
 		return err
 	}
 	if !resp.Success() {
-		c.Alert(errors.New(string(resp.RawBody)))
-		return resp
+		err = newLarkError("RollbackApprovalTask", resp.ApiResp, resp.CodeError, "task_id", currTaskId)
+		c.Alert(err)
+		return err
 	}
 	return nil
 }
@@ -698,37 +827,16 @@ func (c *larkClient) GetAppInfo(appId string) *larkapplication.Application {
 		Lang(`zh_cn`).
 		Build()
 	resp, err := c.client.Application.Application.Get(context.Background(), req)
-	if err != nil || !resp.Success() {
+	if err != nil || !resp.Success() || resp.Data == nil {
 		return nil
 	}
 	return resp.Data.App
 }
 func (c *larkClient) Alert(err error) {
-	if c.debugId == "" {
+	if c.alerter == nil || err == nil {
 		return
 	}
-	client := NewClient(c.debugId, c.debugSecret)
-	appInfo := client.GetAppInfo(c.appId)
-	appName := "未知"
-	if appInfo != nil {
-		appName = *appInfo.AppName
-	}
-	obj := struct {
-		AppId   string `json:"app_id"`
-		AppName string `json:"app_name"`
-		Err     string `json:"err"`
-		ErrTime string `json:"err_time"`
-		Logid   string `json:"logid"`
-	}{
-		AppId:   c.appId,
-		AppName: appName,
-		Err:     err.Error(),
-		ErrTime: time.Now().Format(time.DateTime),
-	}
-	err = client.SendCardMsg(context.Background(), UserId, c.adminUserId, "AAq3zkrIEYCqR", obj)
-	if err != nil {
-		echo.Json(err)
-	}
+	c.alerter.Alert(err)
 }
 func (c *larkClient) AddSign(ctx context.Context, operatorId, approvalCode, instCode, taskId, comment string, addSignUserIds []string, addSignType, approvalMethod int) error {
 	req := larkapproval.NewAddSignInstanceReqBuilder().
@@ -749,8 +857,9 @@ func (c *larkClient) AddSign(ctx context.Context, operatorId, approvalCode, inst
 		return err
 	}
 	if !resp.Success() {
-		c.Alert(errors.New(string(resp.RawBody)))
-		return resp
+		err = newLarkError("AddSign", resp.ApiResp, resp.CodeError, "instance_code", instCode, "task_id", taskId)
+		c.Alert(err)
+		return err
 	}
 	return nil
 }
@@ -774,31 +883,44 @@ func (c *larkClient) ApproveTask(ctx context.Context, approvalCode, instCode, us
 		return err
 	}
 	if !resp.Success() {
-		c.Alert(errors.New(string(resp.RawBody)))
-		return resp
+		err = newLarkError("ApproveTask", resp.ApiResp, resp.CodeError, "instance_code", instCode, "task_id", taskId)
+		c.Alert(err)
+		return err
 	}
 	return nil
 }
 func (c *larkClient) ListRoom(ctx context.Context, roomLevelId string) ([]*larkvc.Room, error) {
-	res := make([]*larkvc.Room, 0)
-	req := larkvc.NewListRoomReqBuilder().
-		UserIdType(UserId).
-		RoomLevelId(roomLevelId).
-		PageSize(100).
-		Build()
-	resp, err := c.client.Vc.Room.List(ctx, req)
-	if err != nil {
-		c.Alert(err)
-		return nil, err
-	}
-	if !resp.Success() {
-		c.Alert(errors.New(string(resp.RawBody)))
-		return nil, resp
-	}
-	for _, room := range resp.Data.Rooms {
-		res = append(res, room)
-	}
-	return res, nil
+	return c.ListRoomPager(roomLevelId).Collect(ctx)
+}
+func (c *larkClient) ListRoomPager(roomLevelId string) *Pager[*larkvc.Room] {
+	return NewPager(func(ctx context.Context, pageToken string) (*Page[*larkvc.Room], error) {
+		req := larkvc.NewListRoomReqBuilder().
+			UserIdType(UserId).
+			RoomLevelId(roomLevelId).
+			PageToken(pageToken).
+			PageSize(100).
+			Build()
+		resp, err := c.client.Vc.Room.List(ctx, req)
+		if err != nil {
+			c.Alert(err)
+			return nil, err
+		}
+		if !resp.Success() {
+			err = newLarkError("ListRoom", resp.ApiResp, resp.CodeError, "room_level_id", roomLevelId)
+			c.Alert(err)
+			return nil, err
+		}
+		if resp.Data == nil {
+			err = newEmptyDataError("ListRoom", resp.ApiResp, "room_level_id", roomLevelId)
+			c.Alert(err)
+			return nil, err
+		}
+		return &Page[*larkvc.Room]{
+			Items:     resp.Data.Rooms,
+			PageToken: larkcore.StringValue(resp.Data.PageToken),
+			HasMore:   larkcore.BoolValue(resp.Data.HasMore),
+		}, nil
+	})
 }
 func (c *larkClient) CheckRoomFree(ctx context.Context, roomId, timeMin, timeMax string) (bool, error) {
 	req := larkcalendar.NewListFreebusyReqBuilder().
@@ -815,8 +937,14 @@ func (c *larkClient) CheckRoomFree(ctx context.Context, roomId, timeMin, timeMax
 		return false, err
 	}
 	if !resp.Success() {
-		c.Alert(errors.New(string(resp.RawBody)))
-		return false, resp
+		err = newLarkError("CheckRoomFree", resp.ApiResp, resp.CodeError, "room_id", roomId)
+		c.Alert(err)
+		return false, err
+	}
+	if resp.Data == nil {
+		err = newEmptyDataError("CheckRoomFree", resp.ApiResp, "room_id", roomId)
+		c.Alert(err)
+		return false, err
 	}
 	if len(resp.Data.FreebusyList) == 0 {
 		return true, nil
@@ -848,8 +976,9 @@ func (c *larkClient) SetCalendarRoom(ctx context.Context, calendarId, eventId, r
 		return err
 	}
 	if !resp.Success() {
-		c.Alert(errors.New(string(resp.RawBody)))
-		return resp
+		err = newLarkError("SetCalendarRoom", resp.ApiResp, resp.CodeError, "event_id", eventId, "room_id", roomId)
+		c.Alert(err)
+		return err
 	}
 	return nil
 }
@@ -873,30 +1002,49 @@ func (c *larkClient) SetCalendarUsers(ctx context.Context, calendarId, eventId s
 		return err
 	}
 	if !resp.Success() {
-		c.Alert(errors.New(string(resp.RawBody)))
-		return resp
+		err = newLarkError("SetCalendarUsers", resp.ApiResp, resp.CodeError, "event_id", eventId)
+		c.Alert(err)
+		return err
 	}
 	return nil
 }
 func (c *larkClient) ListCalendarEvent(ctx context.Context, calendarId string) ([]*larkcalendar.CalendarEvent, error) {
+	return c.ListCalendarEventPager(calendarId).Collect(ctx)
+}
+
+// ListCalendarEventPager 列出日历中从今天零点开始的日程
+func (c *larkClient) ListCalendarEventPager(calendarId string) *Pager[*larkcalendar.CalendarEvent] {
 	now := time.Now()
 	zeroTime := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-	req := larkcalendar.NewListCalendarEventReqBuilder().
-		CalendarId(calendarId).
-		PageSize(500).
-		StartTime(fmt.Sprint(zeroTime.Unix())).
-		UserIdType(UserId).
-		Build()
-	resp, err := c.client.Calendar.CalendarEvent.List(ctx, req)
-	if err != nil {
-		c.Alert(err)
-		return nil, err
-	}
-	if !resp.Success() {
-		c.Alert(errors.New(string(resp.RawBody)))
-		return nil, resp
-	}
-	return resp.Data.Items, nil
+	return NewPager(func(ctx context.Context, pageToken string) (*Page[*larkcalendar.CalendarEvent], error) {
+		req := larkcalendar.NewListCalendarEventReqBuilder().
+			CalendarId(calendarId).
+			PageSize(500).
+			PageToken(pageToken).
+			StartTime(fmt.Sprint(zeroTime.Unix())).
+			UserIdType(UserId).
+			Build()
+		resp, err := c.client.Calendar.CalendarEvent.List(ctx, req)
+		if err != nil {
+			c.Alert(err)
+			return nil, err
+		}
+		if !resp.Success() {
+			err = newLarkError("ListCalendarEvent", resp.ApiResp, resp.CodeError, "calendar_id", calendarId)
+			c.Alert(err)
+			return nil, err
+		}
+		if resp.Data == nil {
+			err = newEmptyDataError("ListCalendarEvent", resp.ApiResp, "calendar_id", calendarId)
+			c.Alert(err)
+			return nil, err
+		}
+		return &Page[*larkcalendar.CalendarEvent]{
+			Items:     resp.Data.Items,
+			PageToken: larkcore.StringValue(resp.Data.PageToken),
+			HasMore:   larkcore.BoolValue(resp.Data.HasMore),
+		}, nil
+	})
 }
 func (c *larkClient) CreateCalendarEvent(ctx context.Context, calendarId, summary, startTs, endTs string) (*larkcalendar.CalendarEvent, error) {
 	req := larkcalendar.NewCreateCalendarEventReqBuilder().
@@ -922,8 +1070,14 @@ func (c *larkClient) CreateCalendarEvent(ctx context.Context, calendarId, summar
 		return nil, err
 	}
 	if !resp.Success() {
-		c.Alert(errors.New(string(resp.RawBody)))
-		return nil, resp
+		err = newLarkError("CreateCalendarEvent", resp.ApiResp, resp.CodeError, "calendar_id", calendarId)
+		c.Alert(err)
+		return nil, err
+	}
+	if resp.Data == nil {
+		err = newEmptyDataError("CreateCalendarEvent", resp.ApiResp, "calendar_id", calendarId)
+		c.Alert(err)
+		return nil, err
 	}
 	return resp.Data.Event, nil
 }
@@ -944,8 +1098,9 @@ func (c *larkClient) CcApprovalInst(ctx context.Context, approvalCode, instCode,
 		return err
 	}
 	if !resp.Success() {
-		c.Alert(errors.New(string(resp.RawBody)))
-		return resp
+		err = newLarkError("CcApprovalInst", resp.ApiResp, resp.CodeError, "instance_code", instCode)
+		c.Alert(err)
+		return err
 	}
 	c.Alert(errors.Errorf("instCode: %v from: %v cc: %v", instCode, fromUserId, ccUserIds))
 	return nil
@@ -966,32 +1121,49 @@ func (c *larkClient) AddInstComment(ctx context.Context, instCode, userId, comme
 		return err
 	}
 	if !resp.Success() {
-		c.Alert(errors.New(string(resp.RawBody)))
-		return resp
+		err = newLarkError("AddInstComment", resp.ApiResp, resp.CodeError, "instance_code", instCode)
+		c.Alert(err)
+		return err
 	}
 	return nil
 }
 func (c *larkClient) SearchAppTableRecord(ctx context.Context, appToken, tableId string, fieldNames []string, info *larkbitable.FilterInfo) ([]*larkbitable.AppTableRecord, error) {
-	req := larkbitable.NewSearchAppTableRecordReqBuilder().
-		AppToken(appToken).
-		TableId(tableId).
-		PageSize(500).
-		Body(larkbitable.NewSearchAppTableRecordReqBodyBuilder().
-			FieldNames(fieldNames).
-			Filter(info).
-			AutomaticFields(false).
-			Build()).
-		Build()
-	resp, err := c.client.Bitable.AppTableRecord.Search(ctx, req)
-	if err != nil {
-		c.Alert(err)
-		return nil, err
-	}
-	if !resp.Success() {
-		c.Alert(errors.New(string(resp.RawBody)))
-		return nil, resp
-	}
-	return resp.Data.Items, nil
+	return c.SearchAppTableRecordPager(appToken, tableId, fieldNames, info).Collect(ctx)
+}
+func (c *larkClient) SearchAppTableRecordPager(appToken, tableId string, fieldNames []string, info *larkbitable.FilterInfo) *Pager[*larkbitable.AppTableRecord] {
+	return NewPager(func(ctx context.Context, pageToken string) (*Page[*larkbitable.AppTableRecord], error) {
+		req := larkbitable.NewSearchAppTableRecordReqBuilder().
+			AppToken(appToken).
+			TableId(tableId).
+			PageSize(500).
+			PageToken(pageToken).
+			Body(larkbitable.NewSearchAppTableRecordReqBodyBuilder().
+				FieldNames(fieldNames).
+				Filter(info).
+				AutomaticFields(false).
+				Build()).
+			Build()
+		resp, err := c.client.Bitable.AppTableRecord.Search(ctx, req)
+		if err != nil {
+			c.Alert(err)
+			return nil, err
+		}
+		if !resp.Success() {
+			err = newLarkError("SearchAppTableRecord", resp.ApiResp, resp.CodeError, "app_token", appToken, "table_id", tableId)
+			c.Alert(err)
+			return nil, err
+		}
+		if resp.Data == nil {
+			err = newEmptyDataError("SearchAppTableRecord", resp.ApiResp, "app_token", appToken, "table_id", tableId)
+			c.Alert(err)
+			return nil, err
+		}
+		return &Page[*larkbitable.AppTableRecord]{
+			Items:     resp.Data.Items,
+			PageToken: larkcore.StringValue(resp.Data.PageToken),
+			HasMore:   larkcore.BoolValue(resp.Data.HasMore),
+		}, nil
+	})
 }
 func (c *larkClient) GetUserAccessToken(ctx context.Context, code string) (string, error) {
 	req := larkauthen.NewCreateOidcAccessTokenReqBuilder().
@@ -1006,10 +1178,16 @@ func (c *larkClient) GetUserAccessToken(ctx context.Context, code string) (strin
 		return "", err
 	}
 	if !resp.Success() {
-		c.Alert(errors.New(string(resp.RawBody)))
-		return "", resp
+		err = newLarkError("GetUserAccessToken", resp.ApiResp, resp.CodeError)
+		c.Alert(err)
+		return "", err
+	}
+	if resp.Data == nil {
+		err = newEmptyDataError("GetUserAccessToken", resp.ApiResp)
+		c.Alert(err)
+		return "", err
 	}
-	return *resp.Data.AccessToken, nil
+	return larkcore.StringValue(resp.Data.AccessToken), nil
 }
 func (c *larkClient) AddAttendanceFlow(ctx context.Context, userId, locationName, checkTime string) error {
 	req := larkattendance.NewBatchCreateUserFlowReqBuilder().
@@ -1032,14 +1210,17 @@ func (c *larkClient) AddAttendanceFlow(ctx context.Context, userId, locationName
 		return err
 	}
 	if !resp.Success() {
-		c.Alert(errors.New(string(resp.RawBody)))
-		return resp
+		err = newLarkError("AddAttendanceFlow", resp.ApiResp, resp.CodeError, "user_id", userId)
+		c.Alert(err)
+		return err
 	}
 	return nil
 }
 func (c *larkClient) ListBitableRecord(ctx context.Context, appToken, tableId, userIdType string, fieldNames []string, sort []*larkbitable.Sort, filter *larkbitable.FilterInfo) ([]*larkbitable.AppTableRecord, error) {
-	res := make([]*larkbitable.AppTableRecord, 0)
-	for hasMore, pageToken := true, ""; hasMore; {
+	return c.ListBitableRecordPager(appToken, tableId, userIdType, fieldNames, sort, filter).Collect(ctx)
+}
+func (c *larkClient) ListBitableRecordPager(appToken, tableId, userIdType string, fieldNames []string, sort []*larkbitable.Sort, filter *larkbitable.FilterInfo) *Pager[*larkbitable.AppTableRecord] {
+	return NewPager(func(ctx context.Context, pageToken string) (*Page[*larkbitable.AppTableRecord], error) {
 		req := larkbitable.NewSearchAppTableRecordReqBuilder().
 			AppToken(appToken).
 			TableId(tableId).
@@ -1058,18 +1239,21 @@ func (c *larkClient) ListBitableRecord(ctx context.Context, appToken, tableId, u
 			return nil, err
 		}
 		if !resp.Success() {
-			c.Alert(errors.New(string(resp.RawBody)))
-			return nil, resp
-		}
-		hasMore = *resp.Data.HasMore
-		if hasMore {
-			pageToken = *resp.Data.PageToken
+			err = newLarkError("ListBitableRecord", resp.ApiResp, resp.CodeError, "app_token", appToken, "table_id", tableId)
+			c.Alert(err)
+			return nil, err
 		}
-		for _, item := range resp.Data.Items {
-			res = append(res, item)
+		if resp.Data == nil {
+			err = newEmptyDataError("ListBitableRecord", resp.ApiResp, "app_token", appToken, "table_id", tableId)
+			c.Alert(err)
+			return nil, err
 		}
-	}
-	return res, nil
+		return &Page[*larkbitable.AppTableRecord]{
+			Items:     resp.Data.Items,
+			PageToken: larkcore.StringValue(resp.Data.PageToken),
+			HasMore:   larkcore.BoolValue(resp.Data.HasMore),
+		}, nil
+	})
 }
 func (c *larkClient) InsertBitableRecord(ctx context.Context, appToken, tableId, userIdType string, records []*larkbitable.AppTableRecord) error {
 	for _, chunk := range _slice.ChunkSlice(records, 500) {
@@ -1087,8 +1271,9 @@ func (c *larkClient) InsertBitableRecord(ctx context.Context, appToken, tableId,
 			return err
 		}
 		if !resp.Success() {
-			c.Alert(errors.New(string(resp.RawBody)))
-			return resp
+			err = newLarkError("InsertBitableRecord", resp.ApiResp, resp.CodeError, "app_token", appToken, "table_id", tableId)
+			c.Alert(err)
+			return err
 		}
 	}
 	return nil
@@ -1106,8 +1291,9 @@ func (c *larkClient) InsertBitable1Record(ctx context.Context, appToken, tableId
 		return err
 	}
 	if !resp.Success() {
-		c.Alert(errors.New(string(resp.RawBody)))
-		return resp
+		err = newLarkError("InsertBitable1Record", resp.ApiResp, resp.CodeError, "app_token", appToken, "table_id", tableId)
+		c.Alert(err)
+		return err
 	}
 	return nil
 }
@@ -1127,8 +1313,9 @@ func (c *larkClient) UpdateBitableRecord(ctx context.Context, appToken, tableId,
 			return err
 		}
 		if !resp.Success() {
-			c.Alert(errors.New(string(resp.RawBody)))
-			return resp
+			err = newLarkError("UpdateBitableRecord", resp.ApiResp, resp.CodeError, "app_token", appToken, "table_id", tableId)
+			c.Alert(err)
+			return err
 		}
 	}
 	return nil
@@ -1144,56 +1331,107 @@ func (c *larkClient) GetSpaceNode(ctx context.Context, objType, token string) (*
 		return nil, err
 	}
 	if !resp.Success() {
-		c.Alert(errors.New(string(resp.RawBody)))
-		return nil, resp
+		err = newLarkError("GetSpaceNode", resp.ApiResp, resp.CodeError, "token", token)
+		c.Alert(err)
+		return nil, err
+	}
+	if resp.Data == nil {
+		err = newEmptyDataError("GetSpaceNode", resp.ApiResp, "token", token)
+		c.Alert(err)
+		return nil, err
 	}
 	return resp.Data.Node, nil
 }
 func (c *larkClient) GetLog(ctx context.Context, appId, apiKey string) ([]*larksecurityandcompliance.OpenapiLog, error) {
-	req := larksecurityandcompliance.NewListDataOpenapiLogReqBuilder().
-		ListOpenapiLogRequest(larksecurityandcompliance.NewListOpenapiLogRequestBuilder().
+	return c.GetLogPager(appId, apiKey).Collect(ctx)
+}
+func (c *larkClient) GetLogPager(appId, apiKey string) *Pager[*larksecurityandcompliance.OpenapiLog] {
+	return NewPager(func(ctx context.Context, pageToken string) (*Page[*larksecurityandcompliance.OpenapiLog], error) {
+		logReqBuilder := larksecurityandcompliance.NewListOpenapiLogRequestBuilder().
 			ApiKeys([]string{apiKey}).
 			StartTime(1724896800).
 			EndTime(1724904000).
 			AppId(appId).
-			PageSize(100).
-			Build()).
-		Build()
-	resp, err := c.client.SecurityAndCompliance.OpenapiLog.ListData(ctx, req)
-	if err != nil {
-		c.Alert(err)
-		return nil, err
-	}
-	if !resp.Success() {
-		c.Alert(errors.New(string(resp.RawBody)))
-		return nil, resp
-	}
-	return resp.Data.Items, nil
+			PageSize(100)
+		if pageToken != "" {
+			logReqBuilder.PageToken(pageToken)
+		}
+		req := larksecurityandcompliance.NewListDataOpenapiLogReqBuilder().
+			ListOpenapiLogRequest(logReqBuilder.Build()).
+			Build()
+		resp, err := c.client.SecurityAndCompliance.OpenapiLog.ListData(ctx, req)
+		if err != nil {
+			c.Alert(err)
+			return nil, err
+		}
+		if !resp.Success() {
+			err = newLarkError("GetLog", resp.ApiResp, resp.CodeError, "app_id", appId)
+			c.Alert(err)
+			return nil, err
+		}
+		if resp.Data == nil {
+			err = newEmptyDataError("GetLog", resp.ApiResp, "app_id", appId)
+			c.Alert(err)
+			return nil, err
+		}
+		return &Page[*larksecurityandcompliance.OpenapiLog]{
+			Items:     resp.Data.Items,
+			PageToken: larkcore.StringValue(resp.Data.PageToken),
+			HasMore:   larkcore.BoolValue(resp.Data.HasMore),
+		}, nil
+	})
 }
 func (c *larkClient) ListParentDeptByDeptId(ctx context.Context, deptIdType string, deptId string) ([]*larkcontact.Department, error) {
-	req := larkcontact.NewParentDepartmentReqBuilder().
-		UserIdType(UserId).
-		DepartmentIdType(deptIdType).
-		DepartmentId(deptId).
-		PageSize(20).
-		Build()
-	resp, err := c.client.Contact.Department.Parent(ctx, req)
+	key := c.cacheKey("parent_dept", deptIdType, deptId)
+	if depts, ok := cacheGet[[]*larkcontact.Department](ctx, c, key); ok {
+		return depts, nil
+	}
+	res, err := c.ListParentDeptPager(deptIdType, deptId).Collect(ctx)
 	if err != nil {
-		c.Alert(err)
 		return nil, err
 	}
-	if !resp.Success() {
-		c.Alert(errors.New(string(resp.RawBody)))
-		return nil, resp
-	}
-	_slice.Reverse(resp.Data.Items)
+	_slice.Reverse(res)
 	deptInfo, err := c.GetDeptById(ctx, deptIdType, deptId)
 	if err != nil {
 		c.Alert(err)
 		return nil, err
 	}
-	resp.Data.Items = append(resp.Data.Items, deptInfo)
-	return resp.Data.Items, nil
+	res = append(res, deptInfo)
+	cacheSet(ctx, c, key, res)
+	return res, nil
+}
+
+// ListParentDeptPager 从直属上级开始逐级向上列出 deptId 的上级部门
+func (c *larkClient) ListParentDeptPager(deptIdType, deptId string) *Pager[*larkcontact.Department] {
+	return NewPager(func(ctx context.Context, pageToken string) (*Page[*larkcontact.Department], error) {
+		req := larkcontact.NewParentDepartmentReqBuilder().
+			UserIdType(UserId).
+			DepartmentIdType(deptIdType).
+			DepartmentId(deptId).
+			PageToken(pageToken).
+			PageSize(20).
+			Build()
+		resp, err := c.client.Contact.Department.Parent(ctx, req)
+		if err != nil {
+			c.Alert(err)
+			return nil, err
+		}
+		if !resp.Success() {
+			err = newLarkError("ListParentDeptByDeptId", resp.ApiResp, resp.CodeError, "department_id", deptId)
+			c.Alert(err)
+			return nil, err
+		}
+		if resp.Data == nil {
+			err = newEmptyDataError("ListParentDeptByDeptId", resp.ApiResp, "department_id", deptId)
+			c.Alert(err)
+			return nil, err
+		}
+		return &Page[*larkcontact.Department]{
+			Items:     resp.Data.Items,
+			PageToken: larkcore.StringValue(resp.Data.PageToken),
+			HasMore:   larkcore.BoolValue(resp.Data.HasMore),
+		}, nil
+	})
 }
 func (c *larkClient) RejectTask(ctx context.Context, approvalCode, instCode, userId, comment, taskId string) error {
 	req := larkapproval.NewRejectTaskReqBuilder().
@@ -1212,28 +1450,46 @@ func (c *larkClient) RejectTask(ctx context.Context, approvalCode, instCode, use
 		return err
 	}
 	if !resp.Success() {
-		c.Alert(errors.New(string(resp.RawBody)))
-		return resp
+		err = newLarkError("RejectTask", resp.ApiResp, resp.CodeError, "instance_code", instCode, "task_id", taskId)
+		c.Alert(err)
+		return err
 	}
 	return nil
 }
 func (c *larkClient) SearchUserApprovalTask(ctx context.Context, userId, taskStatus string) ([]*larkapproval.TaskSearchItem, error) {
-	req := larkapproval.NewSearchTaskReqBuilder().UserIdType(`user_id`).
-		TaskSearch(larkapproval.NewTaskSearchBuilder().
-			UserId(userId).
-			TaskStatus(taskStatus).
-			Build()).
-		Build()
-	resp, err := c.client.Approval.Task.Search(ctx, req)
-	if err != nil {
-		c.Alert(err)
-		return nil, err
-	}
-	if !resp.Success() {
-		c.Alert(errors.New(string(resp.RawBody)))
-		return nil, resp
-	}
-	return resp.Data.TaskList, nil
+	return c.SearchUserApprovalTaskPager(userId, taskStatus).Collect(ctx)
+}
+func (c *larkClient) SearchUserApprovalTaskPager(userId, taskStatus string) *Pager[*larkapproval.TaskSearchItem] {
+	return NewPager(func(ctx context.Context, pageToken string) (*Page[*larkapproval.TaskSearchItem], error) {
+		req := larkapproval.NewSearchTaskReqBuilder().UserIdType(`user_id`).
+			PageSize(200).
+			PageToken(pageToken).
+			TaskSearch(larkapproval.NewTaskSearchBuilder().
+				UserId(userId).
+				TaskStatus(taskStatus).
+				Build()).
+			Build()
+		resp, err := c.client.Approval.Task.Search(ctx, req)
+		if err != nil {
+			c.Alert(err)
+			return nil, err
+		}
+		if !resp.Success() {
+			err = newLarkError("SearchUserApprovalTask", resp.ApiResp, resp.CodeError, "user_id", userId)
+			c.Alert(err)
+			return nil, err
+		}
+		if resp.Data == nil {
+			err = newEmptyDataError("SearchUserApprovalTask", resp.ApiResp, "user_id", userId)
+			c.Alert(err)
+			return nil, err
+		}
+		return &Page[*larkapproval.TaskSearchItem]{
+			Items:     resp.Data.TaskList,
+			PageToken: larkcore.StringValue(resp.Data.PageToken),
+			HasMore:   larkcore.BoolValue(resp.Data.HasMore),
+		}, nil
+	})
 }
 func (c *larkClient) ListLeaveData(ctx context.Context, from, to time.Time, userIds []string) ([]*larkattendance.UserApproval, error) {
 	const maxDays = 30 // 最大支持的时间间隔
@@ -1278,8 +1534,14 @@ func (c *larkClient) ListLeaveData(ctx context.Context, from, to time.Time, user
 				return nil, err
 			}
 			if !resp.Success() {
-				c.Alert(errors.New(string(resp.RawBody)))
-				return nil, resp
+				err = newLarkError("ListLeaveData", resp.ApiResp, resp.CodeError, "user_ids", chunk)
+				c.Alert(err)
+				return nil, err
+			}
+			if resp.Data == nil {
+				err = newEmptyDataError("ListLeaveData", resp.ApiResp, "user_ids", chunk)
+				c.Alert(err)
+				return nil, err
 			}
 			for _, approval := range resp.Data.UserApprovals {
 				if len(approval.Leaves) > 0 {
@@ -1318,8 +1580,9 @@ func (c *larkClient) SetShift(ctx context.Context, groupId, shiftId string, user
 			return err
 		}
 		if !resp.Success() {
-			c.Alert(errors.New(string(resp.RawBody)))
-			return resp
+			err = newLarkError("SetShift", resp.ApiResp, resp.CodeError, "group_id", groupId)
+			c.Alert(err)
+			return err
 		}
 	}
 	return nil
@@ -1336,8 +1599,14 @@ func (c *larkClient) GetAttendanceGroup(ctx context.Context, groupId string) (*l
 		return nil, err
 	}
 	if !resp.Success() {
-		c.Alert(errors.New(string(resp.RawBody)))
-		return nil, resp
+		err = newLarkError("GetAttendanceGroup", resp.ApiResp, resp.CodeError, "group_id", groupId)
+		c.Alert(err)
+		return nil, err
+	}
+	if resp.Data == nil {
+		err = newEmptyDataError("GetAttendanceGroup", resp.ApiResp, "group_id", groupId)
+		c.Alert(err)
+		return nil, err
 	}
 	return resp.Data, nil
 }
@@ -1356,8 +1625,14 @@ func (c *larkClient) CopySpaceNode(ctx context.Context, spaceId, nodeToken, targ
 		return nil, err
 	}
 	if !resp.Success() {
-		c.Alert(errors.New(string(resp.RawBody)))
-		return nil, resp
+		err = newLarkError("CopySpaceNode", resp.ApiResp, resp.CodeError, "node_token", nodeToken)
+		c.Alert(err)
+		return nil, err
+	}
+	if resp.Data == nil {
+		err = newEmptyDataError("CopySpaceNode", resp.ApiResp, "node_token", nodeToken)
+		c.Alert(err)
+		return nil, err
 	}
 	return resp.Data.Node, nil
 }
@@ -1372,8 +1647,9 @@ func (c *larkClient) SubscribeFile(ctx context.Context, fileToken, fileType stri
 		return err
 	}
 	if !resp.Success() {
-		c.Alert(errors.New(string(resp.RawBody)))
-		return resp
+		err = newLarkError("SubscribeFile", resp.ApiResp, resp.CodeError, "file_token", fileToken)
+		c.Alert(err)
+		return err
 	}
 	return nil
 }
@@ -1409,8 +1685,14 @@ func (c *larkClient) ListAttendanceStats(ctx context.Context, from, to time.Time
 			return nil, err
 		}
 		if !resp.Success() {
-			c.Alert(errors.New(string(resp.RawBody)))
-			return nil, resp
+			err = newLarkError("ListAttendanceStats", resp.ApiResp, resp.CodeError, "user_ids", chunk)
+			c.Alert(err)
+			return nil, err
+		}
+		if resp.Data == nil {
+			err = newEmptyDataError("ListAttendanceStats", resp.ApiResp, "user_ids", chunk)
+			c.Alert(err)
+			return nil, err
 		}
 		res = append(res, resp.Data.UserDatas...)
 	}
@@ -1429,8 +1711,14 @@ func (c *larkClient) GetRecord(ctx context.Context, appToken, tableId, recordId
 		return nil, err
 	}
 	if !resp.Success() {
-		c.Alert(errors.New(string(resp.RawBody)))
-		return nil, resp
+		err = newLarkError("GetRecord", resp.ApiResp, resp.CodeError, "app_token", appToken, "table_id", tableId, "record_id", recordId)
+		c.Alert(err)
+		return nil, err
+	}
+	if resp.Data == nil {
+		err = newEmptyDataError("GetRecord", resp.ApiResp, "app_token", appToken, "table_id", tableId, "record_id", recordId)
+		c.Alert(err)
+		return nil, err
 	}
 	return resp.Data.Record, nil
 }
@@ -1446,8 +1734,14 @@ func (c *larkClient) GetProcess(ctx context.Context, processId string) (*larkcor
 		return nil, err
 	}
 	if !resp.Success() {
-		c.Alert(errors.New(string(resp.RawBody)))
-		return nil, resp
+		err = newLarkError("GetProcess", resp.ApiResp, resp.CodeError, "process_id", processId)
+		c.Alert(err)
+		return nil, err
+	}
+	if resp.Data == nil {
+		err = newEmptyDataError("GetProcess", resp.ApiResp, "process_id", processId)
+		c.Alert(err)
+		return nil, err
 	}
 	return resp.Data, nil
 }
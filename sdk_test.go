@@ -0,0 +1,394 @@
+package lark_sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newFakeServer 返回固定 body 的开放平台，获取 tenant_access_token 的请求总是成功
+func newFakeServer(t *testing.T, body string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		if strings.Contains(r.URL.Path, "/auth/v3/") {
+			_, _ = w.Write([]byte(`{"code":0,"msg":"ok","tenant_access_token":"t-test","app_access_token":"a-test","expire":7200}`))
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestClient(t *testing.T, body string) *larkClient {
+	t.Helper()
+	srv := newFakeServer(t, body)
+	return NewClient("cli_test", "secret", WithBaseURL(srv.URL), WithRetryPolicy(RetryPolicy{})).(*larkClient)
+}
+
+func TestMethodsReturnErrorOnBadResponse(t *testing.T) {
+	responses := map[string]string{
+		"failure":   `{"code":99991672,"msg":"permission denied"}`,
+		"null data": `{"code":0,"msg":"success","data":null}`,
+		"malformed": `{"code":0,"msg":"success","data":`,
+	}
+	now := time.Now()
+	methods := map[string]func(ctx context.Context, c *larkClient) error{
+		"ListRoom": func(ctx context.Context, c *larkClient) error {
+			_, err := c.ListRoom(ctx, "room_level")
+			return err
+		},
+		"ListCalendarEvent": func(ctx context.Context, c *larkClient) error {
+			_, err := c.ListCalendarEvent(ctx, "calendar")
+			return err
+		},
+		"CreateCalendarEvent": func(ctx context.Context, c *larkClient) error {
+			_, err := c.CreateCalendarEvent(ctx, "calendar", "summary", "1700000000", "1700003600")
+			return err
+		},
+		"SearchAppTableRecord": func(ctx context.Context, c *larkClient) error {
+			_, err := c.SearchAppTableRecord(ctx, "app", "table", nil, nil)
+			return err
+		},
+		"ListBitableRecord": func(ctx context.Context, c *larkClient) error {
+			_, err := c.ListBitableRecord(ctx, "app", "table", UserId, nil, nil, nil)
+			return err
+		},
+		"GetSpaceNode": func(ctx context.Context, c *larkClient) error {
+			_, err := c.GetSpaceNode(ctx, "wiki", "token")
+			return err
+		},
+		"GetLog": func(ctx context.Context, c *larkClient) error {
+			_, err := c.GetLog(ctx, "cli_test", "api_key")
+			return err
+		},
+		"ListParentDeptByDeptId": func(ctx context.Context, c *larkClient) error {
+			_, err := c.ListParentDeptByDeptId(ctx, DepartmentId, "dept")
+			return err
+		},
+		"SearchUserApprovalTask": func(ctx context.Context, c *larkClient) error {
+			_, err := c.SearchUserApprovalTask(ctx, "user", "PENDING")
+			return err
+		},
+		"ListLeaveData": func(ctx context.Context, c *larkClient) error {
+			_, err := c.ListLeaveData(ctx, now.AddDate(0, 0, -1), now, []string{"user"})
+			return err
+		},
+		"GetAttendanceGroup": func(ctx context.Context, c *larkClient) error {
+			_, err := c.GetAttendanceGroup(ctx, "group")
+			return err
+		},
+		"CopySpaceNode": func(ctx context.Context, c *larkClient) error {
+			_, err := c.CopySpaceNode(ctx, "space", "node", "parent", "name")
+			return err
+		},
+		"ListAttendanceStats": func(ctx context.Context, c *larkClient) error {
+			_, err := c.ListAttendanceStats(ctx, now.AddDate(0, 0, -1), now, []string{"user"})
+			return err
+		},
+		"GetRecord": func(ctx context.Context, c *larkClient) error {
+			_, err := c.GetRecord(ctx, "app", "table", "record")
+			return err
+		},
+		"GetProcess": func(ctx context.Context, c *larkClient) error {
+			_, err := c.GetProcess(ctx, "process")
+			return err
+		},
+		"BatchGetCoreHREmp": func(ctx context.Context, c *larkClient) error {
+			_, err := c.BatchGetCoreHREmp(ctx, []string{"user"})
+			return err
+		},
+		"SearchCoreHREmpPager": func(ctx context.Context, c *larkClient) error {
+			_, err := c.SearchCoreHREmpPager(nil).Collect(ctx)
+			return err
+		},
+		"SearchPreHirePager": func(ctx context.Context, c *larkClient) error {
+			_, err := c.SearchPreHirePager(nil).Collect(ctx)
+			return err
+		},
+		"ListJobData": func(ctx context.Context, c *larkClient) error {
+			_, err := c.ListJobData(ctx, []string{"user"})
+			return err
+		},
+	}
+	for respName, body := range responses {
+		c := newTestClient(t, body)
+		for name, call := range methods {
+			t.Run(respName+"/"+name, func(t *testing.T) {
+				defer func() {
+					if r := recover(); r != nil {
+						t.Fatalf("panic: %v", r)
+					}
+				}()
+				err := call(context.Background(), c)
+				if err == nil {
+					t.Fatal("want error, got nil")
+				}
+				if respName == "failure" && !IsPermissionDenied(err) {
+					t.Fatalf("want permission denied, got %v", err)
+				}
+			})
+		}
+	}
+}
+
+func TestGetAttachmentStreamsBody(t *testing.T) {
+	content := strings.Repeat("a", 1024)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.Contains(r.URL.Path, "/auth/v3/"):
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			_, _ = w.Write([]byte(`{"code":0,"msg":"ok","tenant_access_token":"t-test","expire":7200}`))
+		case r.Header.Get("Authorization") != "Bearer t-test":
+			w.WriteHeader(http.StatusUnauthorized)
+		case strings.HasSuffix(r.URL.Path, "/attachments/missing"):
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":1053002,"msg":"file not found"}`))
+		default:
+			w.Header().Set("Content-Type", "text/plain")
+			w.Header().Set("Content-Disposition", `attachment; filename="../a.txt"`)
+			_, _ = w.Write([]byte(content))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	c := NewClient("cli_test", "secret", WithBaseURL(srv.URL), WithRetryPolicy(RetryPolicy{})).(*larkClient)
+	rc, meta, err := c.GetAttachment(context.Background(), "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil || string(data) != content {
+		t.Fatalf("read %d bytes, err: %v", len(data), err)
+	}
+	if meta.FileName != "a.txt" || meta.ContentType != "text/plain" || meta.Size != int64(len(content)) {
+		t.Fatalf("unexpected meta %+v", meta)
+	}
+
+	_, _, err = c.GetAttachment(context.Background(), "missing")
+	var larkErr *LarkError
+	if !errors.As(err, &larkErr) || larkErr.Code != 1053002 {
+		t.Fatalf("want LarkError 1053002, got %v", err)
+	}
+
+	c = NewClient("cli_test", "secret", WithBaseURL(srv.URL), WithRetryPolicy(RetryPolicy{}), WithMaxDownloadSize(100)).(*larkClient)
+	if _, _, err = c.GetAttachment(context.Background(), "token"); !errors.Is(err, ErrFileTooLarge) {
+		t.Fatalf("want ErrFileTooLarge, got %v", err)
+	}
+}
+
+func TestApprovalFileUploadUrl(t *testing.T) {
+	cases := map[string]string{
+		"https://open.feishu.cn":     "https://www.feishu.cn/approval/openapi/v2/file/upload",
+		"https://open.larksuite.com": "https://www.larksuite.com/approval/openapi/v2/file/upload",
+		"http://127.0.0.1:8080":      "http://127.0.0.1:8080/approval/openapi/v2/file/upload",
+	}
+	for baseUrl, want := range cases {
+		if got := approvalFileUploadUrl(baseUrl); got != want {
+			t.Errorf("approvalFileUploadUrl(%q) = %q, want %q", baseUrl, got, want)
+		}
+	}
+}
+
+func TestParseFormKeepsBadFieldList(t *testing.T) {
+	form := `[{"id":"a","name":"金额","type":"amount","value":1},{"id":"b","name":"明细","type":"fieldList","value":"bad"}]`
+	widgets, err := ParseForm(form)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(widgets) != 2 || widgets[1].Rows != nil || widgets[1].Value != "bad" {
+		t.Fatalf("unexpected widgets %+v", widgets)
+	}
+	if _, err = widgets[1].AsFieldList(); err == nil {
+		t.Fatal("want decode error from AsFieldList")
+	}
+}
+
+func TestCreateApprovalInstDuplicateUuid(t *testing.T) {
+	c := newTestClient(t, `{"code":1390018,"msg":"uuid conflict"}`)
+	_, err := c.CreateApprovalInst(context.Background(), "approval", "user", []interface{}{}, nil, WithInstUuid("uuid"))
+	if !errors.Is(err, ErrDuplicateUuid) {
+		t.Fatalf("want ErrDuplicateUuid, got %v", err)
+	}
+	c = newTestClient(t, `{"code":1390001,"msg":"param is invalid"}`)
+	_, err = c.CreateApprovalInst(context.Background(), "approval", "user", []interface{}{}, nil, WithInstUuid("uuid"))
+	if err == nil || errors.Is(err, ErrDuplicateUuid) {
+		t.Fatalf("want other error, got %v", err)
+	}
+}
+
+func TestNodeDurationsCappedAtInstanceEnd(t *testing.T) {
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.Local)
+	inst := &Instance{
+		Status:  InstRejected,
+		EndTime: start.Add(2 * time.Hour),
+		Tasks: []InstanceTask{
+			{NodeId: "a", StartTime: start, EndTime: start.Add(2 * time.Hour)},
+			{NodeId: "b", StartTime: start},
+		},
+	}
+	durations := inst.NodeDurations(start.Add(48 * time.Hour))
+	if len(durations) != 2 || durations[1].Duration != 2*time.Hour {
+		t.Fatalf("unexpected durations %+v", durations)
+	}
+	inst.Status = InstPending
+	if d := inst.NodeDurations(start.Add(48 * time.Hour)); d[1].Duration != 48*time.Hour {
+		t.Fatalf("pending instance should count to now, got %v", d[1].Duration)
+	}
+}
+
+func TestApprovalEventRejectsInvalidToken(t *testing.T) {
+	called := 0
+	h := NewApprovalEventHandler("v-token", "").OnInstanceStatusChanged(func(ctx context.Context, event *InstanceStatusEvent) error {
+		called++
+		return nil
+	})
+	post := func(token string) int {
+		body := `{"uuid":"u1","token":"` + token + `","ts":"1700000000","type":"event_callback",` +
+			`"event":{"type":"approval_instance","app_id":"cli_test","approval_code":"a","instance_code":"i","status":"APPROVED","operate_time":"1700000000000"}}`
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/event", strings.NewReader(body)))
+		return w.Code
+	}
+	if code := post("forged"); code == http.StatusOK || called != 0 {
+		t.Fatalf("forged event accepted, status: %d, called: %d", code, called)
+	}
+	if code := post("v-token"); code != http.StatusOK || called != 1 {
+		t.Fatalf("valid event rejected, status: %d, called: %d", code, called)
+	}
+}
+
+type countAlerter struct {
+	errs []error
+}
+
+func (a *countAlerter) Alert(err error) {
+	a.errs = append(a.errs, err)
+}
+
+func TestThrottleAlerterIgnoresLogId(t *testing.T) {
+	next := &countAlerter{}
+	a := NewThrottleAlerter(next, time.Minute)
+	for i := 0; i < 5; i++ {
+		a.Alert(&LarkError{Method: "ListRoom", Code: 99991672, Msg: "permission denied", LogId: fmt.Sprintf("log_%d", i)})
+	}
+	if len(next.errs) != 1 {
+		t.Fatalf("want 1 alert forwarded, got %d", len(next.errs))
+	}
+	a.Alert(&LarkError{Method: "ListRoom", Code: 99991400, LogId: "log_x"})
+	if len(next.errs) != 2 {
+		t.Fatalf("different code should be forwarded, got %d alerts", len(next.errs))
+	}
+}
+
+// newTestPager 共 10 条数据，每页 3 条，page token 为该页第一条的下标
+func newTestPager() *Pager[int] {
+	return NewPager(func(ctx context.Context, pageToken string) (*Page[int], error) {
+		start := 0
+		if pageToken != "" {
+			start, _ = strconv.Atoi(pageToken)
+		}
+		page := &Page[int]{}
+		for i := start; i < start+3 && i < 10; i++ {
+			page.Items = append(page.Items, i)
+		}
+		if next := start + 3; next < 10 {
+			page.PageToken, page.HasMore = strconv.Itoa(next), true
+		}
+		return page, nil
+	})
+}
+
+// resumeFrom 从 pageToken 继续拉取，跳过已经返回过的数据
+func resumeFrom(t *testing.T, pageToken string, got []int) []int {
+	t.Helper()
+	rest, err := newTestPager().StartFrom(pageToken).Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[int]bool)
+	for _, v := range got {
+		seen[v] = true
+	}
+	for _, v := range rest {
+		if !seen[v] {
+			got = append(got, v)
+		}
+	}
+	return got
+}
+
+func TestPagerLimitThenResume(t *testing.T) {
+	p := newTestPager().Limit(5)
+	got, err := p.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(got) != "[0 1 2 3 4]" || !p.Done() || p.PageToken() != "3" {
+		t.Fatalf("got %v, token %q", got, p.PageToken())
+	}
+	if all := resumeFrom(t, p.PageToken(), got); fmt.Sprint(all) != "[0 1 2 3 4 5 6 7 8 9]" {
+		t.Fatalf("resume lost data: %v", all)
+	}
+}
+
+func TestPagerBreakThenResume(t *testing.T) {
+	p := newTestPager()
+	got := make([]int, 0)
+	for v, err := range p.All(context.Background()) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, v)
+		if v == 4 {
+			break
+		}
+	}
+	if p.PageToken() != "3" {
+		t.Fatalf("want token of the unfinished page, got %q", p.PageToken())
+	}
+	if all := resumeFrom(t, p.PageToken(), got); fmt.Sprint(all) != "[0 1 2 3 4 5 6 7 8 9]" {
+		t.Fatalf("resume lost data: %v", all)
+	}
+}
+
+func TestGetAttachmentRefreshesRevokedToken(t *testing.T) {
+	var tokens int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/auth/v3/") {
+			tokens++
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			_, _ = fmt.Fprintf(w, `{"code":0,"msg":"ok","tenant_access_token":"t-%d","expire":7200}`, tokens)
+			return
+		}
+		if r.Header.Get("Authorization") != fmt.Sprintf("Bearer t-%d", tokens) || tokens < 2 {
+			// 第一个 token 已被吊销
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":99991663,"msg":"Invalid access token for authorization"}`))
+			return
+		}
+		_, _ = w.Write([]byte("content"))
+	}))
+	t.Cleanup(srv.Close)
+
+	c := NewClient("cli_test", "secret", WithBaseURL(srv.URL), WithRetryPolicy(RetryPolicy{})).(*larkClient)
+	rc, _, err := c.GetAttachment(context.Background(), "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc.Close()
+	if tokens != 2 {
+		t.Fatalf("want token refreshed once, fetched %d times", tokens)
+	}
+}
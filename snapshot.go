@@ -0,0 +1,182 @@
+package lark_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
+	larkcontact "github.com/larksuite/oapi-sdk-go/v3/service/contact/v3"
+)
+
+// OrgSnapshot 某一时刻的组织架构，部门以 department_id、用户以 user_id 为 key
+type OrgSnapshot struct {
+	CapturedAt time.Time                `json:"captured_at"`
+	Depts      map[string]*SnapshotDept `json:"depts"`
+	Users      map[string]*SnapshotUser `json:"users"`
+}
+
+type SnapshotDept struct {
+	DepartmentId     string `json:"department_id"`
+	OpenDepartmentId string `json:"open_department_id"`
+	Name             string `json:"name"`
+	ParentId         string `json:"parent_id"`
+	LeaderUserId     string `json:"leader_user_id"`
+}
+
+type SnapshotUser struct {
+	UserId        string   `json:"user_id"`
+	OpenId        string   `json:"open_id"`
+	Name          string   `json:"name"`
+	DepartmentIds []string `json:"department_ids"`
+	LeaderUserId  string   `json:"leader_user_id"`
+}
+
+// CaptureOrgSnapshot 拉取全部部门和用户，生成组织架构快照
+func (c *larkClient) CaptureOrgSnapshot(ctx context.Context) (*OrgSnapshot, error) {
+	snapshot := &OrgSnapshot{
+		CapturedAt: time.Now(),
+		Depts:      make(map[string]*SnapshotDept),
+		Users:      make(map[string]*SnapshotUser),
+	}
+	depts, err := c.ListChildDeptByDeptId(ctx, DepartmentId, "0")
+	if err != nil {
+		return nil, err
+	}
+	for _, dept := range depts {
+		if dept == nil || dept.DepartmentId == nil {
+			continue
+		}
+		snapshot.Depts[*dept.DepartmentId] = newSnapshotDept(dept)
+	}
+	err = c.StreamAllUser(ctx, func(user *larkcontact.User) error {
+		snapshot.Users[*user.UserId] = newSnapshotUser(user)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+func newSnapshotDept(dept *larkcontact.Department) *SnapshotDept {
+	return &SnapshotDept{
+		DepartmentId:     larkcore.StringValue(dept.DepartmentId),
+		OpenDepartmentId: larkcore.StringValue(dept.OpenDepartmentId),
+		Name:             larkcore.StringValue(dept.Name),
+		ParentId:         larkcore.StringValue(dept.ParentDepartmentId),
+		LeaderUserId:     larkcore.StringValue(dept.LeaderUserId),
+	}
+}
+
+func newSnapshotUser(user *larkcontact.User) *SnapshotUser {
+	deptIds := append([]string(nil), user.DepartmentIds...)
+	sort.Strings(deptIds)
+	return &SnapshotUser{
+		UserId:        larkcore.StringValue(user.UserId),
+		OpenId:        larkcore.StringValue(user.OpenId),
+		Name:          larkcore.StringValue(user.Name),
+		DepartmentIds: deptIds,
+		LeaderUserId:  larkcore.StringValue(user.LeaderUserId),
+	}
+}
+
+func (s *OrgSnapshot) Marshal() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+func UnmarshalOrgSnapshot(data []byte) (*OrgSnapshot, error) {
+	s := &OrgSnapshot{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+	if s.Depts == nil {
+		s.Depts = make(map[string]*SnapshotDept)
+	}
+	if s.Users == nil {
+		s.Users = make(map[string]*SnapshotUser)
+	}
+	return s, nil
+}
+
+type OrgChangeType string
+
+const (
+	UserJoined        OrgChangeType = "user_joined"
+	UserLeft          OrgChangeType = "user_left"
+	UserMoved         OrgChangeType = "user_moved"
+	UserRenamed       OrgChangeType = "user_renamed"
+	UserLeaderChanged OrgChangeType = "user_leader_changed"
+	DeptCreated       OrgChangeType = "dept_created"
+	DeptRemoved       OrgChangeType = "dept_removed"
+	DeptMoved         OrgChangeType = "dept_moved"
+	DeptRenamed       OrgChangeType = "dept_renamed"
+	DeptLeaderChanged OrgChangeType = "dept_leader_changed"
+)
+
+// OrgChange 两个快照之间的一处变化。Id 为 user_id 或 department_id；
+// Old/New 为变化前后的值：名称、上级 user_id、父部门 id，用户调岗时为逗号分隔的部门 id
+type OrgChange struct {
+	Type OrgChangeType `json:"type"`
+	Id   string        `json:"id"`
+	Name string        `json:"name"`
+	Old  string        `json:"old,omitempty"`
+	New  string        `json:"new,omitempty"`
+}
+
+// DiffOrgSnapshot 比较 prev 到 curr 的变化，结果按类型、id 排序
+func DiffOrgSnapshot(prev, curr *OrgSnapshot) []OrgChange {
+	changes := make([]OrgChange, 0)
+	for id, n := range curr.Depts {
+		o, ok := prev.Depts[id]
+		if !ok {
+			changes = append(changes, OrgChange{Type: DeptCreated, Id: id, Name: n.Name})
+			continue
+		}
+		if o.Name != n.Name {
+			changes = append(changes, OrgChange{Type: DeptRenamed, Id: id, Name: n.Name, Old: o.Name, New: n.Name})
+		}
+		if o.ParentId != n.ParentId {
+			changes = append(changes, OrgChange{Type: DeptMoved, Id: id, Name: n.Name, Old: o.ParentId, New: n.ParentId})
+		}
+		if o.LeaderUserId != n.LeaderUserId {
+			changes = append(changes, OrgChange{Type: DeptLeaderChanged, Id: id, Name: n.Name, Old: o.LeaderUserId, New: n.LeaderUserId})
+		}
+	}
+	for id, o := range prev.Depts {
+		if _, ok := curr.Depts[id]; !ok {
+			changes = append(changes, OrgChange{Type: DeptRemoved, Id: id, Name: o.Name})
+		}
+	}
+	for id, n := range curr.Users {
+		o, ok := prev.Users[id]
+		if !ok {
+			changes = append(changes, OrgChange{Type: UserJoined, Id: id, Name: n.Name})
+			continue
+		}
+		if o.Name != n.Name {
+			changes = append(changes, OrgChange{Type: UserRenamed, Id: id, Name: n.Name, Old: o.Name, New: n.Name})
+		}
+		oldDepts, newDepts := strings.Join(o.DepartmentIds, ","), strings.Join(n.DepartmentIds, ",")
+		if oldDepts != newDepts {
+			changes = append(changes, OrgChange{Type: UserMoved, Id: id, Name: n.Name, Old: oldDepts, New: newDepts})
+		}
+		if o.LeaderUserId != n.LeaderUserId {
+			changes = append(changes, OrgChange{Type: UserLeaderChanged, Id: id, Name: n.Name, Old: o.LeaderUserId, New: n.LeaderUserId})
+		}
+	}
+	for id, o := range prev.Users {
+		if _, ok := curr.Users[id]; !ok {
+			changes = append(changes, OrgChange{Type: UserLeft, Id: id, Name: o.Name})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Type != changes[j].Type {
+			return changes[i].Type < changes[j].Type
+		}
+		return changes[i].Id < changes[j].Id
+	})
+	return changes
+}
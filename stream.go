@@ -0,0 +1,217 @@
+package lark_sdk
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	larkcontact "github.com/larksuite/oapi-sdk-go/v3/service/contact/v3"
+	larkehr "github.com/larksuite/oapi-sdk-go/v3/service/ehr/v1"
+)
+
+// Progress 批量拉取的进度，每拉完一页回调一次
+type Progress struct {
+	Pages     int
+	Items     int
+	DeptDone  int // 按部门拉取时已完成的部门数
+	DeptTotal int
+	Elapsed   time.Duration
+}
+
+type StreamOption func(*streamConfig)
+
+type streamConfig struct {
+	onProgress func(Progress)
+}
+
+// WithProgress 设置进度回调
+func WithProgress(fn func(Progress)) StreamOption {
+	return func(cfg *streamConfig) {
+		cfg.onProgress = fn
+	}
+}
+
+type progressTracker struct {
+	cfg      streamConfig
+	start    time.Time
+	progress Progress
+}
+
+func newProgressTracker(opts []StreamOption) *progressTracker {
+	t := &progressTracker{start: time.Now()}
+	for _, opt := range opts {
+		opt(&t.cfg)
+	}
+	return t
+}
+
+func (t *progressTracker) page(items int) {
+	t.progress.Pages++
+	t.progress.Items += items
+	t.report()
+}
+
+func (t *progressTracker) report() {
+	if t.cfg.onProgress == nil {
+		return
+	}
+	t.progress.Elapsed = time.Since(t.start)
+	t.cfg.onProgress(t.progress)
+}
+
+// streamPager 逐页拉取 pager，每条数据回调 fn，fn 返回错误时停止
+func streamPager[T any](ctx context.Context, pager *Pager[T], tracker *progressTracker, fn func(T) error) error {
+	for !pager.Done() {
+		items, err := pager.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if err = fn(item); err != nil {
+				return err
+			}
+		}
+		tracker.page(len(items))
+	}
+	return nil
+}
+
+// StreamAllEmp 逐条回调在职员工，不在内存中保留全量数据
+func (c *larkClient) StreamAllEmp(ctx context.Context, fn func(*larkehr.Employee) error, opts ...StreamOption) error {
+	return streamPager(ctx, c.AllEmpPager(), newProgressTracker(opts), fn)
+}
+
+// StreamAllUserId 逐条回调在职员工的 user_id
+func (c *larkClient) StreamAllUserId(ctx context.Context, fn func(string) error, opts ...StreamOption) error {
+	return c.StreamAllEmp(ctx, func(emp *larkehr.Employee) error {
+		if emp.UserId == nil {
+			return nil
+		}
+		return fn(*emp.UserId)
+	}, opts...)
+}
+
+// StreamAllUser 逐条回调全部用户
+func (c *larkClient) StreamAllUser(ctx context.Context, fn func(*larkcontact.User) error, opts ...StreamOption) error {
+	return c.StreamUserByDeptId(ctx, DepartmentId, "0", fn, opts...)
+}
+
+// StreamUserByDeptId 逐条回调 deptId 及其子部门下的用户，同一用户只回调一次。
+// 配置了 WithConcurrency 时并发拉取各部门，回调顺序与串行拉取一致
+func (c *larkClient) StreamUserByDeptId(ctx context.Context, deptIdType, deptId string, fn func(*larkcontact.User) error, opts ...StreamOption) error {
+	childDeptIds, err := c.ListChildDeptIdByDeptId(ctx, deptIdType, deptId)
+	if err != nil {
+		c.Alert(err)
+		return err
+	}
+	tracker := newProgressTracker(opts)
+	tracker.progress.DeptTotal = len(childDeptIds)
+	seen := make(map[string]struct{})
+	emit := func(user *larkcontact.User) error {
+		if user.UserId == nil {
+			return nil
+		}
+		if _, ok := seen[*user.UserId]; ok {
+			return nil
+		}
+		seen[*user.UserId] = struct{}{}
+		return fn(user)
+	}
+	if c.concurrency > 1 {
+		return c.crawlDeptUsers(ctx, childDeptIds, func(users []*larkcontact.User) error {
+			for _, user := range users {
+				if err := emit(user); err != nil {
+					return err
+				}
+			}
+			tracker.page(len(users))
+			tracker.progress.DeptDone++
+			tracker.report()
+			return nil
+		})
+	}
+	for _, childDeptId := range childDeptIds {
+		if err = streamPager(ctx, c.ListDeptUserPager(DepartmentId, childDeptId), tracker, emit); err != nil {
+			return err
+		}
+		tracker.progress.DeptDone++
+		tracker.report()
+	}
+	return nil
+}
+
+type deptUsers struct {
+	users []*larkcontact.User
+	err   error
+}
+
+// crawlDeptUsers 以 c.concurrency 个并发拉取各部门的用户，按 deptIds 的顺序回调 fn；
+// 部门被回调后才释放并发名额，已拉取未回调的部门数不超过并发数。
+// 任一部门失败或 ctx 取消时停止所有请求，并等待已发出的请求结束后返回
+func (c *larkClient) crawlDeptUsers(ctx context.Context, deptIds []string, fn func([]*larkcontact.User) error) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make([]chan deptUsers, len(deptIds))
+	for i := range results {
+		results[i] = make(chan deptUsers, 1)
+	}
+	sem := make(chan struct{}, c.concurrency)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i, deptId := range deptIds {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			wg.Add(1)
+			go func(i int, deptId string) {
+				defer wg.Done()
+				users, err := c.ListDeptUserPager(DepartmentId, deptId).Collect(ctx)
+				results[i] <- deptUsers{users: users, err: err}
+			}(i, deptId)
+		}
+	}()
+	for i := range deptIds {
+		var r deptUsers
+		select {
+		case r = <-results[i]:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if r.err != nil {
+			return r.err
+		}
+		if err := fn(r.users); err != nil {
+			return err
+		}
+		<-sem
+	}
+	return nil
+}
+
+// ToChan 把 StreamXxx 形式的回调转换为 channel，数据发送完或出错后两个 channel 都会关闭，
+// 调用方不再读取时应取消 ctx
+func ToChan[T any](ctx context.Context, stream func(ctx context.Context, fn func(T) error) error, buffer int) (<-chan T, <-chan error) {
+	ch := make(chan T, buffer)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(errCh)
+		defer close(ch)
+		err := stream(ctx, func(item T) error {
+			select {
+			case ch <- item:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil {
+			errCh <- err
+		}
+	}()
+	return ch, errCh
+}
@@ -2,42 +2,82 @@ package lark_sdk
 
 import (
 	"encoding/json"
+	"fmt"
 
 	larkapproval "github.com/larksuite/oapi-sdk-go/v3/service/approval/v4"
 	larkcorehr "github.com/larksuite/oapi-sdk-go/v3/service/corehr/v2"
 )
 
+// ParseForm 解析审批表单，明细控件的各行解析到 Rows 中，明细的值无法解析时 Rows 为 nil
 func ParseForm(formStr string) ([]FormWidget, error) {
 	res := make([]FormWidget, 0)
 	err := json.Unmarshal([]byte(formStr), &res)
 	if err != nil {
 		return nil, err
 	}
+	parseRows(res)
 	return res, nil
 }
 
+// parseRows 解析明细控件的各行，值无法解析的明细控件 Rows 保持为 nil，原始值仍在 Value 中，
+// 调用 AsFieldList 时返回错误，不影响表单中的其他控件
+func parseRows(widgets []FormWidget) {
+	for i := range widgets {
+		if widgets[i].Type != WidgetFieldList {
+			continue
+		}
+		rows, err := widgets[i].AsFieldList()
+		if err != nil {
+			continue
+		}
+		for _, row := range rows {
+			parseRows(row)
+		}
+		widgets[i].Rows = rows
+	}
+}
+
+// ParseForm2Map 以控件名称为 key 解析审批表单，明细控件中的控件以路径为 key，
+// 如 "费用明细[2].金额" 表示费用明细第 3 行（从 0 开始）的金额
 func ParseForm2Map(formStr string) (map[string]FormWidget, error) {
 	widgets, err := ParseForm(formStr)
 	if err != nil {
 		return nil, err
 	}
 	res := make(map[string]FormWidget)
+	flattenForm(res, "", widgets)
+	return res, nil
+}
+
+func flattenForm(res map[string]FormWidget, prefix string, widgets []FormWidget) {
 	for _, widget := range widgets {
-		res[widget.Name] = widget
+		key := prefix + widget.Name
+		res[key] = widget
+		for i, row := range widget.Rows {
+			flattenForm(res, fmt.Sprintf("%s[%d].", key, i), row)
+		}
 	}
-	return res, nil
 }
 
 func ParseAbstractItem(items []*larkcorehr.ProcessAbstractItem) map[string]string {
 	res := make(map[string]string)
 	for _, item := range items {
+		if item.Name == nil || item.Name.ZhCn == nil || item.Value == nil || item.Value.ZhCn == nil {
+			continue
+		}
 		res[*item.Name.ZhCn] = *item.Value.ZhCn
 	}
 	return res
 }
 
 func CheckNode(instInfo *larkapproval.GetInstanceRespData, nodeName string) (string, bool) {
+	if instInfo == nil {
+		return "", false
+	}
 	for _, task := range instInfo.TaskList {
+		if task.Status == nil || task.NodeName == nil || task.Id == nil {
+			continue
+		}
 		if *task.Status == "PENDING" && *task.NodeName == nodeName {
 			return *task.Id, true
 		}
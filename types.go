@@ -1,5 +1,28 @@
 package lark_sdk
 
+// 审批表单控件类型
+const (
+	WidgetInput        = "input"
+	WidgetTextarea     = "textarea"
+	WidgetNumber       = "number"
+	WidgetAmount       = "amount"
+	WidgetFormula      = "formula"
+	WidgetDate         = "date"
+	WidgetDateInterval = "dateInterval"
+	WidgetRadio        = "radio"
+	WidgetRadioV2      = "radioV2"
+	WidgetCheckbox     = "checkbox"
+	WidgetCheckboxV2   = "checkboxV2"
+	WidgetContact      = "contact"
+	WidgetDepartment   = "department"
+	WidgetAttachment   = "attachmentV2"
+	WidgetAttachmentV1 = "attachment"
+	WidgetImage        = "image"
+	WidgetImageV2      = "imageV2"
+	WidgetFieldList    = "fieldList"
+	WidgetLeaveGroupV2 = "leaveGroupV2"
+)
+
 type FormWidget struct {
 	ID      string      `json:"id"`
 	Name    string      `json:"name"`
@@ -8,4 +31,7 @@ type FormWidget struct {
 	Ext     interface{} `json:"ext"`
 	Option  interface{} `json:"option"`
 	OpenIds []string    `json:"open_ids,omitempty"`
+
+	// Rows 明细控件的各行，由 ParseForm 解析
+	Rows [][]FormWidget `json:"-"`
 }
@@ -0,0 +1,275 @@
+package lark_sdk
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// AmountValue 金额控件的值
+type AmountValue struct {
+	Value    float64
+	Currency string
+}
+
+// DateIntervalValue 日期区间控件的值，Interval 为时长（天）
+type DateIntervalValue struct {
+	Start    time.Time
+	End      time.Time
+	Interval float64
+}
+
+// DepartmentValue 部门控件中的一个部门
+type DepartmentValue struct {
+	Name   string `json:"name"`
+	OpenId string `json:"open_id"`
+}
+
+// ContactValue 联系人控件的值，UserIds 与 OpenIds 一一对应
+type ContactValue struct {
+	UserIds []string
+	OpenIds []string
+}
+
+// LeaveGroupValue 请假控件的值，Unit 为 DAY、HALF_DAY 或 HOUR
+type LeaveGroupValue struct {
+	Name     string
+	Start    time.Time
+	End      time.Time
+	Interval float64
+	Unit     string
+	Reason   string
+}
+
+func (w FormWidget) typeError(want ...string) error {
+	return errors.Wrapf(ErrWidgetType, "widget %s is %s, want %s", w.Name, w.Type, strings.Join(want, "/"))
+}
+
+func (w FormWidget) valueError(err error) error {
+	return errors.Wrapf(ErrWidgetType, "widget %s(%s) has unexpected value %v: %v", w.Name, w.Type, w.Value, err)
+}
+
+func (w FormWidget) is(types ...string) bool {
+	for _, t := range types {
+		if w.Type == t {
+			return true
+		}
+	}
+	return false
+}
+
+// AsString 单行文本、多行文本、单选控件的值
+func (w FormWidget) AsString() (string, error) {
+	if !w.is(WidgetInput, WidgetTextarea, WidgetRadio, WidgetRadioV2) {
+		return "", w.typeError(WidgetInput, WidgetTextarea, WidgetRadio, WidgetRadioV2)
+	}
+	switch v := w.Value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	}
+	return "", w.valueError(errors.New("not a string"))
+}
+
+// AsRadio 单选控件选中的选项
+func (w FormWidget) AsRadio() (string, error) {
+	if !w.is(WidgetRadio, WidgetRadioV2) {
+		return "", w.typeError(WidgetRadio, WidgetRadioV2)
+	}
+	return w.AsString()
+}
+
+// AsCheckbox 多选控件选中的选项
+func (w FormWidget) AsCheckbox() ([]string, error) {
+	if !w.is(WidgetCheckbox, WidgetCheckboxV2) {
+		return nil, w.typeError(WidgetCheckbox, WidgetCheckboxV2)
+	}
+	return toStrings(w.Value), nil
+}
+
+// AsNumber 数字、金额、计算公式控件的值，未填写时返回 0
+func (w FormWidget) AsNumber() (float64, error) {
+	if !w.is(WidgetNumber, WidgetAmount, WidgetFormula) {
+		return 0, w.typeError(WidgetNumber, WidgetAmount, WidgetFormula)
+	}
+	switch v := w.Value.(type) {
+	case nil:
+		return 0, nil
+	case float64:
+		return v, nil
+	case json.Number:
+		return v.Float64()
+	case string:
+		if v == "" {
+			return 0, nil
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0, w.valueError(err)
+		}
+		return f, nil
+	}
+	return 0, w.valueError(errors.New("not a number"))
+}
+
+// AsAmount 金额控件的值和币种
+func (w FormWidget) AsAmount() (*AmountValue, error) {
+	if !w.is(WidgetAmount) {
+		return nil, w.typeError(WidgetAmount)
+	}
+	value, err := w.AsNumber()
+	if err != nil {
+		return nil, err
+	}
+	res := &AmountValue{Value: value}
+	for _, m := range []interface{}{w.Ext, w.Option} {
+		if ext, ok := m.(map[string]interface{}); ok {
+			if currency, ok := ext["currency"].(string); ok {
+				res.Currency = currency
+				break
+			}
+		}
+	}
+	return res, nil
+}
+
+// AsDate 日期控件的值，未填写时返回零值
+func (w FormWidget) AsDate() (time.Time, error) {
+	if !w.is(WidgetDate) {
+		return time.Time{}, w.typeError(WidgetDate)
+	}
+	s, ok := w.Value.(string)
+	if !ok && w.Value != nil {
+		return time.Time{}, w.valueError(errors.New("not a string"))
+	}
+	t, err := parseTime(s)
+	if err != nil {
+		return time.Time{}, w.valueError(err)
+	}
+	return t, nil
+}
+
+// AsDateInterval 日期区间控件的值
+func (w FormWidget) AsDateInterval() (*DateIntervalValue, error) {
+	if !w.is(WidgetDateInterval) {
+		return nil, w.typeError(WidgetDateInterval)
+	}
+	var raw struct {
+		Start    string  `json:"start"`
+		End      string  `json:"end"`
+		Interval float64 `json:"interval"`
+	}
+	if err := decodeValue(w.Value, &raw); err != nil {
+		return nil, w.valueError(err)
+	}
+	res := &DateIntervalValue{Interval: raw.Interval}
+	var err error
+	if res.Start, err = parseTime(raw.Start); err != nil {
+		return nil, w.valueError(err)
+	}
+	if res.End, err = parseTime(raw.End); err != nil {
+		return nil, w.valueError(err)
+	}
+	return res, nil
+}
+
+// AsContact 联系人控件的值
+func (w FormWidget) AsContact() (*ContactValue, error) {
+	if !w.is(WidgetContact) {
+		return nil, w.typeError(WidgetContact)
+	}
+	return &ContactValue{UserIds: toStrings(w.Value), OpenIds: w.OpenIds}, nil
+}
+
+// AsDepartment 部门控件的值
+func (w FormWidget) AsDepartment() ([]DepartmentValue, error) {
+	if !w.is(WidgetDepartment) {
+		return nil, w.typeError(WidgetDepartment)
+	}
+	res := make([]DepartmentValue, 0)
+	if err := decodeValue(w.Value, &res); err != nil {
+		return nil, w.valueError(err)
+	}
+	return res, nil
+}
+
+// AsAttachment 附件控件中的文件
+func (w FormWidget) AsAttachment() ([]FormFile, error) {
+	if !w.is(WidgetAttachment, WidgetAttachmentV1) {
+		return nil, w.typeError(WidgetAttachment, WidgetAttachmentV1)
+	}
+	return FormFiles([]FormWidget{w}), nil
+}
+
+// AsImage 图片控件中图片的下载链接
+func (w FormWidget) AsImage() ([]string, error) {
+	if !w.is(WidgetImage, WidgetImageV2) {
+		return nil, w.typeError(WidgetImage, WidgetImageV2)
+	}
+	return toStrings(w.Value), nil
+}
+
+// AsFieldList 明细控件的各行
+func (w FormWidget) AsFieldList() ([][]FormWidget, error) {
+	if !w.is(WidgetFieldList) {
+		return nil, w.typeError(WidgetFieldList)
+	}
+	if w.Rows != nil {
+		return w.Rows, nil
+	}
+	res := make([][]FormWidget, 0)
+	if err := decodeValue(w.Value, &res); err != nil {
+		return nil, w.valueError(err)
+	}
+	return res, nil
+}
+
+// AsLeaveGroup 请假控件的值
+func (w FormWidget) AsLeaveGroup() (*LeaveGroupValue, error) {
+	if !w.is(WidgetLeaveGroupV2) {
+		return nil, w.typeError(WidgetLeaveGroupV2)
+	}
+	var raw struct {
+		Name     string  `json:"name"`
+		Start    string  `json:"start"`
+		End      string  `json:"end"`
+		Interval float64 `json:"interval"`
+		Unit     string  `json:"unit"`
+		Reason   string  `json:"reason"`
+	}
+	if err := decodeValue(w.Value, &raw); err != nil {
+		return nil, w.valueError(err)
+	}
+	res := &LeaveGroupValue{Name: raw.Name, Interval: raw.Interval, Unit: raw.Unit, Reason: raw.Reason}
+	var err error
+	if res.Start, err = parseTime(raw.Start); err != nil {
+		return nil, w.valueError(err)
+	}
+	if res.End, err = parseTime(raw.End); err != nil {
+		return nil, w.valueError(err)
+	}
+	return res, nil
+}
+
+// decodeValue 把 json 解析出的 interface{} 转为指定结构，值为空时保持 out 不变
+func decodeValue(v interface{}, out interface{}) error {
+	if v == nil {
+		return nil
+	}
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bs, out)
+}
+
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}